@@ -0,0 +1,52 @@
+package ini
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToAnyMap returns the contents of the ini indexed by section then key, with
+// values converted to bool, int64 or float64 when they look like one, and kept
+// as string otherwise. See InferValue for the rules applied.
+func (i Ini) ToAnyMap() map[string]map[string]any {
+	res := make(map[string]map[string]any)
+
+	for n, s := range i {
+		sub := make(map[string]any)
+		for k, v := range s {
+			sub[k] = InferValue(v)
+		}
+		res[n] = sub
+	}
+
+	return res
+}
+
+// InferValue converts v to a typed value:
+//
+//   - "true" and "false" (in any case) become a bool
+//   - integers in canonical decimal form become an int64 (so "0755" or "+1"
+//     are kept as strings)
+//   - finite decimal numbers such as "1.5" or "2e3" become a float64
+//   - anything else is returned unchanged as a string
+func InferValue(v string) any {
+	switch strings.ToLower(v) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil && strconv.FormatInt(n, 10) == v {
+		return n
+	}
+
+	if strings.Trim(v, "0123456789.eE+-") == "" && strings.ContainsAny(v, "0123456789") && strings.ContainsAny(v, ".eE") {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,28 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestInferValue(t *testing.T) {
+	tests := map[string]any{
+		"true":  true,
+		"FALSE": false,
+		"42":    int64(42),
+		"-3":    int64(-3),
+		"0755":  "0755",
+		"1.5":   1.5,
+		"2e3":   2000.0,
+		"inf":   "inf",
+		"hello": "hello",
+		"":      "",
+	}
+
+	for in, want := range tests {
+		if got := ini.InferValue(in); got != want {
+			t.Errorf("InferValue(%q) = %#v, expected %#v", in, got, want)
+		}
+	}
+}
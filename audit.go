@@ -0,0 +1,53 @@
+package ini
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry describes a change along with the time it was made.
+type AuditEntry struct {
+	Time time.Time
+	Change
+}
+
+// Audit registers fn to be called for every modification, with the time of
+// the change. See OnChange for restrictions on fn.
+func (i *IniSafe) Audit(fn func(AuditEntry)) {
+	i.OnChange(func(c Change) {
+		fn(AuditEntry{Time: time.Now(), Change: c})
+	})
+}
+
+// AuditTo writes one JSON object per line to w for every modification, with
+// the fields time, kind, section, key, old and new. Values of keys for which
+// IsSecretKey returns true are replaced with RedactedValue. Write errors are
+// ignored.
+func (i *IniSafe) AuditTo(w io.Writer) {
+	var lk sync.Mutex
+	enc := json.NewEncoder(w)
+
+	i.Audit(func(e AuditEntry) {
+		if IsSecretKey(e.Key) {
+			if e.Kind != Added {
+				e.Old = RedactedValue
+			}
+			if e.Kind != Removed {
+				e.New = RedactedValue
+			}
+		}
+
+		lk.Lock()
+		defer lk.Unlock()
+		enc.Encode(struct {
+			Time    time.Time `json:"time"`
+			Kind    string    `json:"kind"`
+			Section string    `json:"section"`
+			Key     string    `json:"key"`
+			Old     string    `json:"old,omitempty"`
+			New     string    `json:"new,omitempty"`
+		}{e.Time, e.Kind.String(), e.Section, e.Key, e.Old, e.New})
+	})
+}
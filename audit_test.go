@@ -0,0 +1,36 @@
+package ini_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafeAuditTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	i := ini.NewSafe()
+	i.AuditTo(buf)
+
+	i.Set("db", "host", "a")
+	i.Set("db", "host", "b")
+	i.Set("db", "password", "hunter2")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected audit log:\n%s", buf.String())
+	}
+
+	var e map[string]string
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatalf("failed to decode audit entry: %s", err)
+	}
+	if e["kind"] != "modified" || e["section"] != "db" || e["key"] != "host" || e["old"] != "a" || e["new"] != "b" || e["time"] == "" {
+		t.Errorf("unexpected audit entry %v", e)
+	}
+	if strings.Contains(lines[2], "hunter2") {
+		t.Errorf("secret value was logged: %s", lines[2])
+	}
+}
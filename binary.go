@@ -0,0 +1,99 @@
+package ini
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binaryVersion is the first byte of data produced by MarshalBinary
+const binaryVersion = 1
+
+var errInvalidBinary = errors.New("ini: invalid binary data")
+
+// MarshalBinary implements encoding.BinaryMarshaler, so an Ini can be stored
+// in gob encoded data or sent over RPC without going through the text
+// format. Sections and keys are encoded in alphabetical order, so equal
+// values always produce the same data.
+func (i Ini) MarshalBinary() ([]byte, error) {
+	res := []byte{binaryVersion}
+	res = binary.AppendUvarint(res, uint64(len(i)))
+
+	for _, n := range sortedKeys(i) {
+		s := i[n]
+		res = appendBinaryString(res, n)
+		res = binary.AppendUvarint(res, uint64(len(s)))
+		for _, k := range sortedKeys(s) {
+			res = appendBinaryString(res, k)
+			res = appendBinaryString(res, s[k])
+		}
+	}
+
+	return res, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The current values
+// are replaced with the decoded ones.
+func (i *Ini) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errInvalidBinary
+	}
+	d := &binaryDecoder{buf: data[1:]}
+
+	res := make(Ini)
+	for n := d.uvarint(); n > 0 && d.err == nil; n-- {
+		name := d.string()
+		s := make(map[string]string)
+		for c := d.uvarint(); c > 0 && d.err == nil; c-- {
+			k := d.string()
+			s[k] = d.string()
+		}
+		res[name] = s
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.buf) != 0 {
+		return errInvalidBinary
+	}
+
+	*i = res
+	return nil
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// binaryDecoder reads data produced by MarshalBinary. Errors are sticky.
+type binaryDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errInvalidBinary
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *binaryDecoder) string() string {
+	l := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if l > uint64(len(d.buf)) {
+		d.err = errInvalidBinary
+		return ""
+	}
+	s := string(d.buf[:l])
+	d.buf = d.buf[l:]
+	return s
+}
@@ -0,0 +1,49 @@
+package ini_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestBinary(t *testing.T) {
+	i := ini.MustParse("a=1\n[s]\nb=\"multi\\nline\"\nc=\n")
+
+	data, err := i.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	var res ini.Ini
+	if err := res.UnmarshalBinary(data); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	if d := res.Diff(i); len(d) != 0 {
+		t.Errorf("unexpected result: %v", d)
+	}
+
+	if err := res.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("expected an error for truncated data")
+	}
+}
+
+func TestBinaryGob(t *testing.T) {
+	type entry struct {
+		Name   string
+		Config ini.Ini
+	}
+	in := entry{Name: "test", Config: ini.MustParse("a=1\n[s]\nb=2\n")}
+
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(in); err != nil {
+		t.Fatalf("failed to encode: %s", err)
+	}
+	var out entry
+	if err := gob.NewDecoder(buf).Decode(&out); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if d := out.Config.Diff(in.Config); len(d) != 0 || out.Name != in.Name {
+		t.Errorf("unexpected result: %v", d)
+	}
+}
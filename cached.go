@@ -0,0 +1,104 @@
+package ini
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CachedFile serves values from an ini file kept in memory. The file is
+// checked for changes (modification time or size) at most once per TTL, and
+// reloaded when it changed, so frequent lookups don't hit the filesystem.
+type CachedFile struct {
+	path string
+	ttl  time.Duration
+	data *IniSafe
+
+	next atomic.Int64 // time of next check, in unix nanoseconds
+
+	lk    sync.Mutex
+	mtime time.Time
+	size  int64
+	err   error
+}
+
+// NewCachedFile loads the file at path and returns a CachedFile checking it
+// for changes at most once per ttl.
+func NewCachedFile(path string, ttl time.Duration) (*CachedFile, error) {
+	c := &CachedFile{path: path, ttl: ttl, data: NewSafe()}
+
+	c.lk.Lock()
+	defer c.lk.Unlock()
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// refresh checks the file for changes if the ttl has expired
+func (c *CachedFile) refresh() {
+	now := time.Now().UnixNano()
+	if now < c.next.Load() {
+		return
+	}
+
+	c.lk.Lock()
+	defer c.lk.Unlock()
+
+	if now < c.next.Load() {
+		// checked by another goroutine meanwhile
+		return
+	}
+	c.err = c.reload()
+}
+
+// reload reloads the file if it changed. The caller must hold c.lk.
+func (c *CachedFile) reload() error {
+	c.next.Store(time.Now().Add(c.ttl).UnixNano())
+
+	st, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	if st.ModTime().Equal(c.mtime) && st.Size() == c.size {
+		return nil
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := c.data.Reload(f); err != nil {
+		return err
+	}
+	c.mtime, c.size = st.ModTime(), st.Size()
+	return nil
+}
+
+// Get returns a value for a given key, after checking the file for changes
+// if needed.
+func (c *CachedFile) Get(section, key string) (string, bool) {
+	c.refresh()
+	return c.data.Get(section, key)
+}
+
+// Ini returns the IniSafe holding the values, after checking the file for
+// changes if needed. It can be used to subscribe to changes.
+func (c *CachedFile) Ini() *IniSafe {
+	c.refresh()
+	return c.data
+}
+
+// Err returns the error encountered during the last check, if any. When the
+// file cannot be read or parsed, the previously loaded values keep being
+// served.
+func (c *CachedFile) Err() error {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+
+	return c.err
+}
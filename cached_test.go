@@ -0,0 +1,45 @@
+package ini_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestCachedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ini")
+	if err := os.WriteFile(path, []byte("[db]\nhost=a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ini.NewCachedFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to load file: %s", err)
+	}
+
+	if err := os.WriteFile(path, []byte("[db]\nhost=bb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Get("db", "host"); v != "a" {
+		t.Errorf("file was reloaded before ttl expired, got %#v", v)
+	}
+
+	c, err = ini.NewCachedFile(path, 0)
+	if err != nil {
+		t.Fatalf("failed to load file: %s", err)
+	}
+	if err := os.WriteFile(path, []byte("[db]\nhost=ccc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Get("db", "host"); v != "ccc" {
+		t.Errorf("file was not reloaded, got %#v", v)
+	}
+
+	os.WriteFile(path, []byte("invalid"), 0644)
+	if v, _ := c.Get("db", "host"); v != "ccc" || c.Err() == nil {
+		t.Errorf("expected previous value and an error, got %#v %v", v, c.Err())
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+)
+
+// formats maps format names to the matching decode and encode functions. ini
+// values are unquoted when read and quoted when needed when written, so
+// values with surrounding spaces or line breaks survive conversions.
+var formats = map[string]struct {
+	decode func([]byte) (ini.Ini, error)
+	encode func(ini.Ini) ([]byte, error)
+}{
+	"ini": {
+		func(b []byte) (ini.Ini, error) {
+			i := ini.New()
+			return i, i.LoadWithOptions(bytes.NewReader(b), &ini.LoadOptions{Unquote: true})
+		},
+		func(i ini.Ini) ([]byte, error) {
+			buf := &bytes.Buffer{}
+			err := i.WriteWithOptions(buf, &ini.WriteOptions{Quote: ini.QuoteMinimal})
+			return buf.Bytes(), err
+		},
+	},
+	"json":       {ini.FromJSON, ini.Ini.ToJSON},
+	"yaml":       {ini.FromYAML, ini.Ini.ToYAML},
+	"toml":       {ini.FromTOML, ini.Ini.ToTOML},
+	"properties": {ini.FromProperties, func(i ini.Ini) ([]byte, error) { return i.ToProperties(), nil }},
+}
+
+// formatOf returns the format matching the extension of path
+func formatOf(path string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case "yml":
+		return "yaml"
+	case "conf", "cfg":
+		return "ini"
+	default:
+		return ext
+	}
+}
+
+func cmdConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := fs.String("from", "", "input format (ini, json, yaml, toml, properties), guessed from the file extension by default")
+	to := fs.String("to", "", "output format, guessed from the file extension by default")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 2 {
+		return errUsage
+	}
+
+	in, out := fs.Arg(0), fs.Arg(1)
+	if *from == "" {
+		*from = "ini"
+		if in != "" && in != "-" {
+			*from = formatOf(in)
+		}
+	}
+	if *to == "" {
+		*to = "ini"
+		if out != "" && out != "-" {
+			*to = formatOf(out)
+		}
+	}
+
+	dec, ok := formats[*from]
+	if !ok {
+		return fmt.Errorf("unsupported input format %s", *from)
+	}
+	enc, ok := formats[*to]
+	if !ok {
+		return fmt.Errorf("unsupported output format %s", *to)
+	}
+
+	var data []byte
+	var err error
+	if in == "" || in == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	i, err := dec.decode(data)
+	if err != nil {
+		return err
+	}
+	data, err = enc.encode(i)
+	if err != nil {
+		return err
+	}
+
+	if out == "" || out == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(out, data, 0644)
+}
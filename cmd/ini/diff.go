@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// cmdDiff prints the differences between two files and exits with status 1
+// if there are any, or 2 if the files couldn't be read.
+func cmdDiff(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	a, err := load(args[0], nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ini diff: %s\n", err)
+		return exitError(2)
+	}
+	b, err := load(args[1], nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ini diff: %s\n", err)
+		return exitError(2)
+	}
+
+	changes := a.Diff(b)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if len(changes) > 0 {
+		return exitError(1)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func cmdFmt(args []string) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	sortFlag := fs.Bool("s", false, "sort sections and keys")
+	write := fs.Bool("w", false, "write result to (source) file instead of stdout")
+	list := fs.Bool("l", false, "list files whose formatting differs")
+	spaced := fs.Bool("spaced", false, "write spaces around '='")
+	preserve := fs.Bool("preserve-spacing", false, "keep the spacing around '=' of each line")
+	unquote := fs.Bool("unquote", false, "remove quotes around values that do not need them")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	opts := &ini.FormatOptions{Sort: *sortFlag, Unquote: *unquote}
+	switch {
+	case *preserve:
+		opts.Spacing = ini.SpacingPreserve
+	case *spaced:
+		opts.Spacing = ini.SpacingAround
+	}
+
+	if fs.NArg() == 0 {
+		if *write || *list {
+			return errUsage
+		}
+		return ini.Format(os.Stdout, os.Stdin, opts)
+	}
+
+	for _, path := range fs.Args() {
+		if *write {
+			changed, err := ini.FormatFile(path, opts)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if *list && changed {
+				fmt.Println(path)
+			}
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		buf := &bytes.Buffer{}
+		if err := ini.Format(buf, bytes.NewReader(src), opts); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if *list {
+			if !bytes.Equal(src, buf.Bytes()) {
+				fmt.Println(path)
+			}
+		} else if _, err := io.Copy(os.Stdout, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
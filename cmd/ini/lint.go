@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+)
+
+// cmdLint validates files against a schema and prints one file:line
+// diagnostic per problem found. It exits with status 1 if any problem was
+// found.
+func cmdLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "", "schema file")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *schemaPath == "" || fs.NArg() == 0 {
+		return errUsage
+	}
+
+	def, err := load(*schemaPath, nil)
+	if err != nil {
+		return err
+	}
+	schema, err := ini.ParseSchema(def)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *schemaPath, err)
+	}
+
+	failed := false
+	for _, path := range fs.Args() {
+		i, err := load(path, nil)
+		if err != nil {
+			fmt.Printf("%s: %s\n", path, err)
+			failed = true
+			continue
+		}
+
+		errs := schema.Validate(i)
+		if len(errs) == 0 {
+			continue
+		}
+		failed = true
+
+		lines, err := positions(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range errs {
+			line, ok := lines[e.Section+"\x00"+e.Key]
+			if !ok {
+				// missing keys are reported on the section header
+				line = lines[e.Section+"\x00"]
+			}
+			fmt.Printf("%s:%d: %s\n", path, line, e)
+		}
+	}
+
+	if failed {
+		return exitError(1)
+	}
+	return nil
+}
+
+// positions returns the line number of each section header and key in the
+// file at path, indexed by "section\x00key" (with an empty key for section
+// headers).
+func positions(path string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	res := map[string]int{"root\x00": 1}
+	p := &ini.Parser{
+		OnSection: func(name string, line int) error {
+			name = strings.ToLower(name)
+			if _, ok := res[name+"\x00"]; !ok {
+				res[name+"\x00"] = line
+			}
+			return nil
+		},
+		OnKeyValue: func(section, key, value string, line int) error {
+			res[strings.ToLower(section)+"\x00"+strings.ToLower(key)] = line
+			return nil
+		},
+	}
+
+	return res, p.Parse(f)
+}
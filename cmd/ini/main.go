@@ -0,0 +1,173 @@
+// Command ini reads and modifies ini files from the command line.
+//
+//	ini get config.ini section.key
+//	ini set config.ini section.key value
+//	ini unset config.ini section.key
+//	ini convert [-from format] [-to format] [input [output]]
+//	ini diff a.ini b.ini
+//	ini fmt [-s] [-w] [-l] [-unquote] [-spaced|-preserve-spacing] [files...]
+//	ini lint -schema schema.ini files...
+//
+// Names without a dot refer to keys of the root section. Values are read and
+// written as found in the file, without removing or adding quotes. Files are
+// rewritten atomically, keeping their comments.
+//
+// convert reads standard input and writes standard output when no file is
+// given. Supported formats are ini, json, yaml, toml and properties. Quotes
+// around ini values are removed when reading, and added when writing values
+// that need them.
+//
+// diff prints added, removed and modified values, and exits with status 1 if
+// the files differ.
+//
+// fmt rewrites files in canonical form, keeping comments and values as
+// found, unless -unquote is given. It reads standard input when no file is
+// given.
+//
+// lint validates files against a schema (see ini.ParseSchema) and prints
+// file:line diagnostics, exiting with status 1 if any problem was found.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]*command{
+	"get":     {"get <file> <section.key>", cmdGet},
+	"set":     {"set <file> <section.key> <value>", cmdSet},
+	"unset":   {"unset <file> <section.key>", cmdUnset},
+	"convert": {"convert [-from format] [-to format] [input [output]]", cmdConvert},
+	"diff":    {"diff <a.ini> <b.ini>", cmdDiff},
+	"fmt":     {"fmt [-s] [-w] [-l] [-unquote] [-spaced|-preserve-spacing] [files...]", cmdFmt},
+	"lint":    {"lint -schema <schema.ini> <files...>", cmdLint},
+}
+
+// errUsage is returned by commands called with invalid arguments
+var errUsage = errors.New("invalid arguments")
+
+// exitError is returned by commands to exit with a specific status without
+// printing anything more
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if err == errUsage {
+			fmt.Fprintf(os.Stderr, "usage: ini %s\n", cmd.usage)
+			os.Exit(2)
+		}
+		if code, ok := err.(exitError); ok {
+			os.Exit(int(code))
+		}
+		fmt.Fprintf(os.Stderr, "ini %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "usage:\n")
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "\tini %s\n", commands[n].usage)
+	}
+	os.Exit(2)
+}
+
+// splitName splits "section.key" on its last dot
+func splitName(name string) (string, string) {
+	pos := strings.LastIndexByte(name, '.')
+	if pos < 0 {
+		return "root", name
+	}
+	return name[:pos], name[pos+1:]
+}
+
+func load(path string, opts *ini.LoadOptions) (ini.Ini, error) {
+	i := ini.New()
+	if err := i.LoadFile(path, opts); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// edit loads the file at path with its comments, calls fn to modify it and
+// saves it back. If create is true, a missing file is created.
+func edit(path string, create bool, fn func(i ini.Ini)) error {
+	c := &ini.Comments{}
+	i, err := load(path, &ini.LoadOptions{Comments: c, KeepEmptySections: true})
+	if err != nil {
+		if !create || !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		i = ini.New()
+	}
+
+	fn(i)
+	return i.SaveFileWithOptions(path, &ini.WriteOptions{Comments: c})
+}
+
+func cmdGet(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	i, err := load(args[0], nil)
+	if err != nil {
+		return err
+	}
+
+	section, key := splitName(args[1])
+	v, ok := i.Get(section, key)
+	if !ok {
+		return fmt.Errorf("%s: not found", args[1])
+	}
+	fmt.Println(v)
+	return nil
+}
+
+func cmdSet(args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	section, key := splitName(args[1])
+	return edit(args[0], true, func(i ini.Ini) {
+		i.Set(section, key, args[2])
+	})
+}
+
+func cmdUnset(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	section, key := splitName(args[1])
+	return edit(args[0], false, func(i ini.Ini) {
+		i.Unset(section, key)
+	})
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+// run calls a subcommand and returns what it printed on standard output
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	err = commands[args[0]].run(args[1:])
+	w.Close()
+	return <-out, err
+}
+
+// writeFile creates a file in a temporary directory and returns its path
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSetUnset(t *testing.T) {
+	path := writeFile(t, "t.ini", "; config\n\npath=\"/foo bar\"\n\n; server settings\n[s]\n; listen port\nport=80\n")
+
+	for _, v := range []string{"1", "2"} {
+		if _, err := run(t, "set", path, "s.x", v); err != nil {
+			t.Fatalf("set failed: %s", err)
+		}
+	}
+	expect := "; config\n\npath=\"/foo bar\"\n\n; server settings\n[s]\n; listen port\nport=80\nx=2\n\n"
+	if s := readFile(t, path); s != expect {
+		t.Errorf("unexpected file after set %q", s)
+	}
+
+	if out, err := run(t, "get", path, "path"); err != nil || out != "\"/foo bar\"\n" {
+		t.Errorf("unexpected get result %q %v", out, err)
+	}
+	if _, err := run(t, "get", path, "s.missing"); err == nil {
+		t.Errorf("expected an error for a missing key")
+	}
+
+	if _, err := run(t, "unset", path, "s.port"); err != nil {
+		t.Fatalf("unset failed: %s", err)
+	}
+	expect = "; config\n\npath=\"/foo bar\"\n\n; server settings\n[s]\nx=2\n\n"
+	if s := readFile(t, path); s != expect {
+		t.Errorf("unexpected file after unset %q", s)
+	}
+
+	// set creates missing files, unset does not
+	created := filepath.Join(t.TempDir(), "new.ini")
+	if _, err := run(t, "set", created, "a", "b"); err != nil {
+		t.Fatalf("set failed: %s", err)
+	}
+	if s := readFile(t, created); s != "a=b\n\n" {
+		t.Errorf("unexpected new file %q", s)
+	}
+	if _, err := run(t, "unset", filepath.Join(t.TempDir(), "missing.ini"), "a"); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+
+	if _, err := run(t, "set", path, "a"); err != errUsage {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := writeFile(t, "a.ini", "x=1\ny=2\n")
+	b := writeFile(t, "b.ini", "x=1\ny=3\nz=4\n")
+
+	out, err := run(t, "diff", a, b)
+	if err != exitError(1) {
+		t.Errorf("expected exit status 1, got %v", err)
+	}
+	if out != "~ root.y=2 -> 3\n+ root.z=4\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	if out, err := run(t, "diff", a, a); err != nil || out != "" {
+		t.Errorf("unexpected result for identical files %q %v", out, err)
+	}
+	if _, err := run(t, "diff", a, filepath.Join(t.TempDir(), "missing.ini")); err != exitError(2) {
+		t.Errorf("expected exit status 2, got %v", err)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	src := "b = \"C:\\xyz\"\n\n\n; about a\na=1\n"
+	path := writeFile(t, "t.ini", src)
+
+	out, err := run(t, "fmt", path)
+	if err != nil {
+		t.Fatalf("fmt failed: %s", err)
+	}
+	expect := "b=\"C:\\xyz\"\n\n; about a\na=1\n"
+	if out != expect {
+		t.Errorf("unexpected output %q", out)
+	}
+	if s := readFile(t, path); s != src {
+		t.Errorf("file changed without -w")
+	}
+
+	if out, err := run(t, "fmt", "-l", "-w", path); err != nil || out != path+"\n" {
+		t.Errorf("unexpected result %q %v", out, err)
+	}
+	if s := readFile(t, path); s != expect {
+		t.Errorf("unexpected file %q", s)
+	}
+	if out, err := run(t, "fmt", "-l", path); err != nil || out != "" {
+		t.Errorf("formatted file listed: %q %v", out, err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	schema := writeFile(t, "schema.ini", "[server]\nport = int,required\n")
+	good := writeFile(t, "good.ini", "[server]\nport=80\n")
+	bad := writeFile(t, "bad.ini", "[server]\nport=http\n")
+
+	if out, err := run(t, "lint", "-schema", schema, good); err != nil || out != "" {
+		t.Errorf("unexpected result for a valid file %q %v", out, err)
+	}
+	out, err := run(t, "lint", "-schema", schema, bad)
+	if err != exitError(1) {
+		t.Errorf("expected exit status 1, got %v", err)
+	}
+	if !strings.HasPrefix(out, bad+":2: ") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+// loadUnquote loads the file at path with LoadOptions.Unquote
+func loadUnquote(t *testing.T, path string) ini.Ini {
+	t.Helper()
+	i, err := load(path, &ini.LoadOptions{Unquote: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestConvert(t *testing.T) {
+	src := "a=\" x\"\npath=\"C:\\\\temp\"\n\n[s]\nq='\"quoted\"'\n\n"
+	in := writeFile(t, "a.ini", src)
+	dir := t.TempDir()
+
+	for _, format := range []string{"json", "yaml", "toml", "properties"} {
+		out := filepath.Join(dir, "b."+format)
+		if _, err := run(t, "convert", in, out); err != nil {
+			t.Fatalf("failed to convert to %s: %s", format, err)
+		}
+		back := filepath.Join(dir, format+".ini")
+		if _, err := run(t, "convert", out, back); err != nil {
+			t.Fatalf("failed to convert from %s: %s", format, err)
+		}
+		if d := loadUnquote(t, back).Diff(loadUnquote(t, in)); len(d) != 0 {
+			t.Errorf("%s round-trip changed values: %v", format, d)
+		}
+	}
+
+	// values are converted without their ini quoting
+	out, err := run(t, "convert", "-to", "json", in)
+	if err != nil {
+		t.Fatalf("failed to convert: %s", err)
+	}
+	if !strings.Contains(out, `" x"`) || !strings.Contains(out, `"C:\\temp"`) || !strings.Contains(out, `"\"quoted\""`) {
+		t.Errorf("unexpected json %s", out)
+	}
+}
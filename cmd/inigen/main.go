@@ -0,0 +1,309 @@
+// Command inigen generates a Go struct and a matching load function from a
+// sample ini file or a schema, so configuration values can be accessed with
+// compile-time checked names and types.
+//
+// It is meant to be used with go:generate:
+//
+//	//go:generate inigen -in config.sample.ini -type Config -o config_gen.go
+//
+// Each section becomes a field of the generated type holding a struct with
+// one field per key. Keys of the root section are stored directly in the
+// generated type. When reading a sample, field types are inferred from the
+// values (see ini.InferValue). With -schema, the input is read as a schema
+// (see ini.ParseSchema) and the rule types are used instead. Names that
+// would produce the same Go field, such as keys "max-conn" and "max_conn",
+// cause an error.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+)
+
+type field struct {
+	name string // Go name
+	key  string // ini key
+	typ  string // Go type
+}
+
+type section struct {
+	name   string // Go name
+	ini    string // ini section name
+	fields []*field
+}
+
+func main() {
+	in := flag.String("in", "", "sample ini file or schema to read")
+	out := flag.String("o", "", "output file (default: stdout)")
+	typeName := flag.String("type", "Config", "name of the generated type")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name (default: $GOPACKAGE)")
+	schema := flag.Bool("schema", false, "read input as a schema instead of a sample")
+	flag.Parse()
+
+	if *in == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*in, *out, *typeName, *pkg, *schema); err != nil {
+		fmt.Fprintf(os.Stderr, "inigen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, typeName, pkg string, schema bool) error {
+	src := ini.New()
+	if err := src.LoadFile(in, nil); err != nil {
+		return err
+	}
+
+	var types map[string]map[string]string
+	if schema {
+		s, err := ini.ParseSchema(src)
+		if err != nil {
+			return err
+		}
+		types = schemaTypes(s)
+	} else {
+		types = sampleTypes(src)
+	}
+
+	code, err := generate(types, typeName, pkg)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0644)
+}
+
+// sampleTypes returns the Go type of each value of i, as inferred from the
+// value itself
+func sampleTypes(i ini.Ini) map[string]map[string]string {
+	res := make(map[string]map[string]string)
+	for n, s := range i {
+		res[n] = make(map[string]string)
+		for k, v := range s {
+			switch ini.InferValue(v).(type) {
+			case bool:
+				res[n][k] = "bool"
+			case int64:
+				res[n][k] = "int64"
+			case float64:
+				res[n][k] = "float64"
+			default:
+				res[n][k] = "string"
+			}
+		}
+	}
+	return res
+}
+
+// schemaTypes returns the Go type of each key of s
+func schemaTypes(s ini.Schema) map[string]map[string]string {
+	res := make(map[string]map[string]string)
+	for n, rules := range s {
+		res[n] = make(map[string]string)
+		for k, r := range rules {
+			switch r.Type {
+			case "int":
+				res[n][k] = "int64"
+			case "float":
+				res[n][k] = "float64"
+			case "bool":
+				res[n][k] = "bool"
+			case "duration":
+				res[n][k] = "time.Duration"
+			default:
+				res[n][k] = "string"
+			}
+		}
+	}
+	return res
+}
+
+func generate(types map[string]map[string]string, typeName, pkg string) ([]byte, error) {
+	var root *section
+	var sections []*section
+
+	for _, n := range sortedKeys(types) {
+		s := &section{name: goName(n), ini: n}
+		for _, k := range sortedKeys(types[n]) {
+			s.fields = append(s.fields, &field{name: goName(k), key: k, typ: types[n][k]})
+		}
+		if n == "root" {
+			root = s
+		} else {
+			sections = append(sections, s)
+		}
+	}
+
+	if err := checkNames(root, sections); err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]bool)
+	for _, s := range types {
+		for _, typ := range s {
+			switch typ {
+			case "string":
+			case "time.Duration":
+				imports["fmt"] = true
+				imports["time"] = true
+			default:
+				imports["fmt"] = true
+				imports["strconv"] = true
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by inigen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	for _, imp := range sortedKeys(imports) {
+		fmt.Fprintf(buf, "\t%q\n", imp)
+	}
+	fmt.Fprintf(buf, "\n\t\"github.com/KarpelesLab/ini\"\n)\n\n")
+
+	fmt.Fprintf(buf, "// %s holds the values of a configuration file.\ntype %s struct {\n", typeName, typeName)
+	if root != nil {
+		for _, f := range root.fields {
+			fmt.Fprintf(buf, "\t%s %s // %s\n", f.name, f.typ, f.key)
+		}
+	}
+	for _, s := range sections {
+		fmt.Fprintf(buf, "\t%s %s%s // [%s]\n", s.name, typeName, s.name, s.ini)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	for _, s := range sections {
+		fmt.Fprintf(buf, "// %s%s holds the values of section [%s].\ntype %s%s struct {\n", typeName, s.name, s.ini, typeName, s.name)
+		for _, f := range s.fields {
+			fmt.Fprintf(buf, "\t%s %s // %s\n", f.name, f.typ, f.key)
+		}
+		fmt.Fprintf(buf, "}\n\n")
+	}
+
+	fmt.Fprintf(buf, "// Load%s reads the ini file at path into a new %s.\n", typeName, typeName)
+	fmt.Fprintf(buf, "func Load%s(path string) (*%s, error) {\n", typeName, typeName)
+	fmt.Fprintf(buf, "\ti := ini.New()\n\tif err := i.LoadFile(path, nil); err != nil {\n\t\treturn nil, err\n\t}\n\treturn Decode%s(i)\n}\n\n", typeName)
+
+	fmt.Fprintf(buf, "// Decode%s reads the values of i into a new %s.\n", typeName, typeName)
+	fmt.Fprintf(buf, "func Decode%s(i ini.Ini) (*%s, error) {\n\tc := &%s{}\n", typeName, typeName, typeName)
+	if root != nil {
+		for _, f := range root.fields {
+			writeDecode(buf, "root", f, "c."+f.name)
+		}
+	}
+	for _, s := range sections {
+		for _, f := range s.fields {
+			writeDecode(buf, s.ini, f, "c."+s.name+"."+f.name)
+		}
+	}
+	fmt.Fprintf(buf, "\treturn c, nil\n}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// checkNames returns an error if two fields of a generated struct would have
+// the same Go name, such as for keys "max-conn" and "max_conn", or for root
+// key "server" and section [server]
+func checkNames(root *section, sections []*section) error {
+	check := func(where string, names map[string]string, name, desc string) error {
+		if prev, ok := names[name]; ok {
+			return fmt.Errorf("%s and %s%s both map to field %s", prev, desc, where, name)
+		}
+		names[name] = desc
+		return nil
+	}
+
+	top := make(map[string]string)
+	if root != nil {
+		for _, f := range root.fields {
+			if err := check("", top, f.name, "key "+f.key); err != nil {
+				return err
+			}
+		}
+	}
+	for _, s := range sections {
+		if err := check("", top, s.name, "section ["+s.ini+"]"); err != nil {
+			return err
+		}
+		names := make(map[string]string)
+		for _, f := range s.fields {
+			if err := check(" in section ["+s.ini+"]", names, f.name, "key "+f.key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeDecode writes the code storing the value of key f in dst
+func writeDecode(buf *bytes.Buffer, section string, f *field, dst string) {
+	fmt.Fprintf(buf, "\tif v, ok := i.Get(%q, %q); ok {\n", section, f.key)
+
+	var parse string
+	switch f.typ {
+	case "string":
+		fmt.Fprintf(buf, "\t\t%s = v\n\t}\n", dst)
+		return
+	case "int64":
+		parse = "strconv.ParseInt(v, 0, 64)"
+	case "float64":
+		parse = "strconv.ParseFloat(v, 64)"
+	case "bool":
+		parse = "strconv.ParseBool(v)"
+	case "time.Duration":
+		parse = "time.ParseDuration(v)"
+	}
+	fmt.Fprintf(buf, "\t\tx, err := %s\n", parse)
+	fmt.Fprintf(buf, "\t\tif err != nil {\n\t\t\treturn nil, fmt.Errorf(\"%s.%s: %%w\", err)\n\t\t}\n", section, f.key)
+	fmt.Fprintf(buf, "\t\t%s = x\n\t}\n", dst)
+}
+
+// goName converts an ini name such as "max-conn" or "server.http" to an
+// exported Go identifier such as "MaxConn" or "ServerHttp"
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z':
+			if upper {
+				c -= 'a' - 'A'
+			}
+			b.WriteRune(c)
+			upper = false
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b.WriteRune(c)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+
+	res := b.String()
+	if res == "" || (res[0] >= '0' && res[0] <= '9') {
+		res = "X" + res
+	}
+	return res
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	code, err := generate(map[string]map[string]string{
+		"root":        {"debug": "bool"},
+		"server.http": {"max-conn": "int64", "timeout": "time.Duration"},
+	}, "Config", "main")
+	if err != nil {
+		t.Fatalf("failed to generate code: %s", err)
+	}
+
+	// ignore alignment
+	res := strings.Join(strings.Fields(string(code)), " ")
+	for _, s := range []string{
+		"Debug bool",
+		"ServerHttp ConfigServerHttp",
+		"MaxConn int64",
+		"Timeout time.Duration",
+		`i.Get("server.http", "max-conn")`,
+	} {
+		if !strings.Contains(res, s) {
+			t.Errorf("generated code is missing %q:\n%s", s, code)
+		}
+	}
+}
+
+func TestGenerateCollisions(t *testing.T) {
+	for _, types := range []map[string]map[string]string{
+		{"root": {"server": "string"}, "server": {"port": "int64"}},
+		{"server": {"max-conn": "int64", "max_conn": "int64"}},
+		{"server.http": {"port": "int64"}, "server_http": {"port": "int64"}},
+	} {
+		if _, err := generate(types, "Config", "main"); err == nil {
+			t.Errorf("expected an error for %v", types)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ini
+
+import "strings"
+
+// Comments holds the comments of an ini file, which an Ini does not store.
+// Set it in LoadOptions to collect the comments found while loading, and in
+// WriteOptions to write them back, so they survive a round-trip. The zero
+// value is ready to use.
+type Comments struct {
+	header   []string            // comment lines, as found in the file
+	sections map[string][]string // by lowercase section name
+	keys     map[string][]string // by lowercase "section\x00key"
+	footer   []string            // at the end of the file
+}
+
+// SetHeader sets the comment written at the top of the file, separated from
+// the rest by a blank line, such as "Generated by X, do not edit". Each line
+// of text becomes a separate comment line. An empty text removes the header.
+func (c *Comments) SetHeader(text string) {
+	c.header = commentLines(text)
+}
+
+// Header returns the comment found at the top of the file, or set with
+// SetHeader, without the comment characters.
+func (c *Comments) Header() string {
+	return commentText(c.header)
+}
+
+// SetFooter sets the comment written at the end of the file. Each line of
+// text becomes a separate comment line. An empty text removes the footer.
+func (c *Comments) SetFooter(text string) {
+	c.footer = commentLines(text)
+}
+
+// Footer returns the comment found at the end of the file, after the last
+// value, or set with SetFooter, without the comment characters.
+func (c *Comments) Footer() string {
+	return commentText(c.footer)
+}
+
+// SetSectionComment sets the comment written above the header of a section.
+// Each line of text becomes a separate comment line. An empty text removes
+// the comment.
+func (c *Comments) SetSectionComment(section, text string) {
+	section = strings.ToLower(section)
+	if text == "" {
+		delete(c.sections, section)
+		return
+	}
+	if c.sections == nil {
+		c.sections = make(map[string][]string)
+	}
+	c.sections[section] = commentLines(text)
+}
+
+// SectionComment returns the comment found above the header of a section,
+// or set with SetSectionComment, without the comment characters.
+func (c *Comments) SectionComment(section string) string {
+	return commentText(c.sections[strings.ToLower(section)])
+}
+
+// SetComment sets the comment written above a key. Each line of text becomes
+// a separate comment line. An empty text removes the comment.
+func (c *Comments) SetComment(section, key, text string) {
+	c.setKey(strings.ToLower(section), strings.ToLower(key), commentLines(text))
+}
+
+// Comment returns the comment found above a key, or set with SetComment,
+// without the comment characters.
+func (c *Comments) Comment(section, key string) string {
+	return commentText(c.keys[strings.ToLower(section)+"\x00"+strings.ToLower(key)])
+}
+
+func (c *Comments) setKey(section, key string, lines []string) {
+	if len(lines) == 0 {
+		delete(c.keys, section+"\x00"+key)
+		return
+	}
+	if c.keys == nil {
+		c.keys = make(map[string][]string)
+	}
+	c.keys[section+"\x00"+key] = lines
+}
+
+// commentLines returns the comment lines used to write text
+func commentLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	res := strings.Split(text, "\n")
+	for n, l := range res {
+		res[n] = strings.TrimRight("; "+l, " \r")
+	}
+	return res
+}
+
+// commentText returns the text of comment lines. Invalid lines kept in
+// lenient mode are skipped.
+func commentText(lines []string) string {
+	res := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if !strings.HasPrefix(l, ";") {
+			continue
+		}
+		res = append(res, strings.TrimPrefix(l[1:], " "))
+	}
+	return strings.Join(res, "\n")
+}
+
+// commentCollector fills Comments from Parser callbacks. Its methods can be
+// called on a nil collector and do nothing.
+type commentCollector struct {
+	c       *Comments
+	pending []string
+	last    int  // line of the last pending comment
+	started bool // a section or value was found
+}
+
+func (cc *commentCollector) comment(text string, line int) error {
+	cc.pending = append(cc.pending, ";"+text)
+	cc.last = line
+	return nil
+}
+
+// invalid keeps a line that could not be parsed as is, along with comments
+func (cc *commentCollector) invalid(text string, line int) {
+	cc.pending = append(cc.pending, text)
+	cc.last = line
+}
+
+// element is called when a section or value is found on line, and returns
+// the comments attached to it
+func (cc *commentCollector) element(line int) []string {
+	if cc == nil {
+		return nil
+	}
+	res := cc.pending
+	if !cc.started && len(res) > 0 && line > cc.last+1 {
+		// comments at the top, separated by a blank line
+		cc.c.header = res
+		res = nil
+	}
+	cc.pending = nil
+	cc.started = true
+	return res
+}
+
+// section is called when a section header is found on line
+func (cc *commentCollector) section(name string, line int) {
+	if lines := cc.element(line); len(lines) > 0 {
+		if cc.c.sections == nil {
+			cc.c.sections = make(map[string][]string)
+		}
+		cc.c.sections[name] = lines
+	}
+}
+
+// end is called once the whole input was parsed
+func (cc *commentCollector) end() {
+	if cc == nil {
+		return
+	}
+	if !cc.started {
+		if len(cc.pending) > 0 {
+			cc.c.header = cc.pending
+		}
+	} else {
+		cc.c.footer = cc.pending
+	}
+	cc.pending = nil
+}
+
+// key is called when a value is found on line
+func (cc *commentCollector) key(section, key string, line int) {
+	if lines := cc.element(line); len(lines) > 0 {
+		cc.c.setKey(section, key, lines)
+	}
+}
@@ -0,0 +1,127 @@
+package ini_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestCommentsHeader(t *testing.T) {
+	src := "; Generated by test\n;do not edit\n\na=1\n"
+
+	c := &ini.Comments{}
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Comments: c}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if h := c.Header(); h != "Generated by test\ndo not edit" {
+		t.Errorf("unexpected header %q", h)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	if buf.String() != src+"\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	c.SetHeader("new header")
+	buf.Reset()
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c})
+	if buf.String() != "; new header\n\na=1\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	// a comment directly above the first value is not a header
+	c = &ini.Comments{}
+	i.LoadWithOptions(strings.NewReader("; about a\na=1\n"), &ini.LoadOptions{Comments: c})
+	if h := c.Header(); h != "" {
+		t.Errorf("unexpected header %q", h)
+	}
+}
+
+func TestCommentsSection(t *testing.T) {
+	src := "a=1\n\n[other]\nb=2\n\n; the server\n; listens on port\n[Server]\nport=80\n\n"
+
+	c := &ini.Comments{}
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Comments: c}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if s := c.SectionComment("server"); s != "the server\nlistens on port" {
+		t.Errorf("unexpected section comment %q", s)
+	}
+
+	buf := &bytes.Buffer{}
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c})
+	if buf.String() != strings.Replace(src, "Server", "server", 1) {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	c.SetSectionComment("server", "")
+	c.SetSectionComment("OTHER", "other things")
+	buf.Reset()
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c})
+	if buf.String() != "a=1\n\n; other things\n[other]\nb=2\n\n[server]\nport=80\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestCommentsKey(t *testing.T) {
+	src := "; about a\na=1\nb=2\n\n[s]\n; about c\n; on two lines\nc=3\n\n"
+
+	c := &ini.Comments{}
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Comments: c}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if s := c.Comment("root", "a"); s != "about a" {
+		t.Errorf("unexpected comment %q", s)
+	}
+	if s := c.Comment("S", "C"); s != "about c\non two lines" {
+		t.Errorf("unexpected comment %q", s)
+	}
+
+	buf := &bytes.Buffer{}
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c})
+	if buf.String() != src {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	c.SetComment("root", "a", "")
+	c.SetComment("root", "b", "about b")
+	buf.Reset()
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c, BlankBeforeComment: true})
+	if buf.String() != "a=1\n\n; about b\nb=2\n\n[s]\n; about c\n; on two lines\nc=3\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestCommentsLenient(t *testing.T) {
+	src := "a=1\n%include other.ini\n\n[s]\n  some extension\nb=2\n; end\n"
+
+	if err := ini.New().Load(strings.NewReader(src)); err == nil {
+		t.Errorf("expected an error for invalid lines")
+	}
+
+	c := &ini.Comments{}
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Comments: c, Lenient: true}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if v, _ := i.Get("s", "b"); v != "2" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if s := c.Footer(); s != "end" {
+		t.Errorf("unexpected footer %q", s)
+	}
+
+	buf := &bytes.Buffer{}
+	i.WriteWithOptions(buf, &ini.WriteOptions{Comments: c})
+	if buf.String() != "a=1\n\n%include other.ini\n[s]\n  some extension\nb=2\n; end\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
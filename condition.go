@@ -0,0 +1,59 @@
+package ini
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// conditionalSection splits a lowercase section header such as
+// "server if os=linux" into the section name and whether its conditions
+// match the current platform. Headers without conditions always match, and
+// "if os=linux" alone refers to the root section. The conditions follow the
+// last " if ", so names such as "gif if os=linux" are supported.
+func conditionalSection(header string) (string, bool, error) {
+	var name, cond string
+	if pos := strings.LastIndex(header, " if "); pos >= 0 {
+		name, cond = header[:pos], header[pos+4:]
+	} else if c, ok := strings.CutPrefix(header, "if "); ok {
+		cond = c
+	} else {
+		return header, true, nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "root"
+	}
+
+	match := true
+	for _, c := range strings.Split(cond, ",") {
+		ok, err := checkCondition(strings.TrimSpace(c))
+		if err != nil {
+			return "", false, err
+		}
+		match = match && ok
+	}
+	return name, match, nil
+}
+
+// checkCondition evaluates a condition such as "os=linux" or "arch!=arm64"
+func checkCondition(c string) (bool, error) {
+	k, v, found := strings.Cut(c, "=")
+	if !found {
+		return false, fmt.Errorf("invalid condition %q", c)
+	}
+	neg := strings.HasSuffix(k, "!")
+	k = strings.TrimSpace(strings.TrimSuffix(k, "!"))
+	v = strings.TrimSpace(v)
+
+	var cur string
+	switch k {
+	case "os":
+		cur = runtime.GOOS
+	case "arch":
+		cur = runtime.GOARCH
+	default:
+		return false, fmt.Errorf("unknown condition %q", k)
+	}
+	return (cur == v) != neg, nil
+}
@@ -0,0 +1,510 @@
+package ini
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode stores the values of the ini in the struct pointed to by v. Fields are
+// matched to keys of the root section by name, or by the name given in an
+// `ini:"name"` tag; fields tagged `ini:"-"` are ignored. Fields holding a
+// struct are matched to sections, and structs nested in those to dotted
+// sub-sections: field TLS of field Server is decoded from section "server.tls".
+// Fields of embedded structs are handled as if they were fields of the outer
+// struct, unless the embedded struct is given a name in its tag. Names are
+// compared without regard to case. Fields without a matching key are left
+// unchanged, so they can be given default values before calling Decode.
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration,
+// types implementing encoding.TextUnmarshaler, and slices of these, whose
+// values are separated by commas. time.Time values use the RFC 3339 format, or
+// the layout given in a `layout:"2006-01-02"` tag, which also applies to
+// *time.Time and []time.Time fields. Pointers to these types, such as *int, are
+// only set when the key exists, so they remain nil for missing keys. Likewise,
+// fields holding a pointer to a struct are only set if their section has
+// values. Values that cannot be converted cause a *KeyError.
+func (i Ini) Decode(v any) error {
+	return i.DecodeWithOptions(v, nil)
+}
+
+// DecodeOptions holds settings used by DecodeWithOptions,
+// DecodeSectionWithOptions and DecodePrefixWithOptions.
+type DecodeOptions struct {
+	// NameMapper returns the name of the key or section matching a field
+	// without a name in its tag. By default the field name is used.
+	NameMapper NameMapper
+
+	// DisallowUnknownKeys causes an *UnknownKeysError to be returned if the
+	// decoded sections hold keys that do not match any field, such as a
+	// misspelled setting. All other values are still decoded. When decoding
+	// a single section, only that section and its sub-sections are checked.
+	DisallowUnknownKeys bool
+}
+
+// UnknownKeysError is returned when DisallowUnknownKeys is set and keys do
+// not match any field.
+type UnknownKeysError struct {
+	Keys []string // unknown keys as "section.key", sorted
+}
+
+func (e *UnknownKeysError) Error() string {
+	return "ini: unknown keys: " + strings.Join(e.Keys, ", ")
+}
+
+// DecodeWithOptions decodes the ini like Decode, using the given options.
+// opts can be nil.
+func (i Ini) DecodeWithOptions(v any, opts *DecodeOptions) error {
+	rv, err := structPtr(v)
+	if err != nil {
+		return err
+	}
+	d := newDecoder(i, opts)
+	if err := d.decodeStruct("root", rv); err != nil {
+		return err
+	}
+	return d.checkUnknown(func(string) bool { return true })
+}
+
+// DecodeSection stores the values of a section in the struct pointed to by
+// v, matching keys to fields like Decode does for the root section. Nested
+// structs are decoded from sub-sections of section.
+func (i Ini) DecodeSection(section string, v any) error {
+	return i.DecodeSectionWithOptions(section, v, nil)
+}
+
+// DecodeSectionWithOptions decodes a section like DecodeSection, using the
+// given options. opts can be nil.
+func (i Ini) DecodeSectionWithOptions(section string, v any, opts *DecodeOptions) error {
+	rv, err := structPtr(v)
+	if err != nil {
+		return err
+	}
+	section = strings.ToLower(section)
+	d := newDecoder(i, opts)
+	if err := d.decodeStruct(section, rv); err != nil {
+		return err
+	}
+	return d.checkUnknown(func(n string) bool {
+		return n == section || strings.HasPrefix(n, section+".")
+	})
+}
+
+// DecodePrefix decodes each section whose name starts with prefix into an
+// entry of the map pointed to by m, keyed by the rest of the section name.
+// For example with prefix "upstream.", section "upstream.api" is decoded
+// with DecodeSection into the entry "api". m must point to a map of structs
+// or of pointers to structs, such as *map[string]Upstream; the map is
+// created if nil. Sections below an entry, such as "upstream.api.tls", are
+// decoded into the nested struct fields of that entry.
+func (i Ini) DecodePrefix(prefix string, m any) error {
+	return i.DecodePrefixWithOptions(prefix, m, nil)
+}
+
+// DecodePrefixWithOptions decodes sections like DecodePrefix, using the given
+// options. opts can be nil.
+func (i Ini) DecodePrefixWithOptions(prefix string, m any, opts *DecodeOptions) error {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Map || rv.Elem().Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("ini: DecodePrefix needs a pointer to a map indexed by string, got %T", m)
+	}
+	mv := rv.Elem()
+	elem := mv.Type().Elem()
+	isPtr := elem.Kind() == reflect.Pointer
+	if isPtr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("ini: DecodePrefix needs a map of structs, got %T", m)
+	}
+	if mv.IsNil() {
+		mv.Set(reflect.MakeMap(mv.Type()))
+	}
+
+	d := newDecoder(i, opts)
+	prefix = strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	for _, n := range sortedKeys(i) {
+		name, ok := strings.CutPrefix(n, prefix)
+		if !ok || name == "" {
+			continue
+		}
+		// sub-sections such as "upstream.api.tls" belong to entry "api"
+		name, _, _ = strings.Cut(name, ".")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		n = prefix + name
+		key := reflect.ValueOf(name).Convert(mv.Type().Key())
+
+		// start from the existing entry, if any, to keep its values
+		ev := reflect.New(elem)
+		if cur := mv.MapIndex(key); cur.IsValid() {
+			if isPtr {
+				if !cur.IsNil() {
+					ev = cur
+				}
+			} else {
+				ev.Elem().Set(cur)
+			}
+		}
+		if err := d.decodeStruct(n, ev.Elem()); err != nil {
+			return err
+		}
+		if isPtr {
+			mv.SetMapIndex(key, ev)
+		} else {
+			mv.SetMapIndex(key, ev.Elem())
+		}
+	}
+	return d.checkUnknown(func(n string) bool {
+		return strings.HasPrefix(n, prefix) && n != prefix
+	})
+}
+
+// structPtr returns the struct pointed to by v
+func structPtr(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("ini: cannot decode into %T, need a pointer to a struct", v)
+	}
+	return rv.Elem(), nil
+}
+
+// decoder holds the state of a decoding operation
+type decoder struct {
+	i     Ini
+	opts  *DecodeOptions
+	known map[string]bool // "section\x00key" of fields, if needed
+	found int             // number of values found
+
+	allocating map[nilPointer]bool // nil pointers being decoded
+}
+
+func newDecoder(i Ini, opts *DecodeOptions) *decoder {
+	d := &decoder{i: i, opts: opts}
+	if opts != nil && opts.DisallowUnknownKeys {
+		d.known = make(map[string]bool)
+	}
+	return d
+}
+
+// checkUnknown returns an *UnknownKeysError if the sections accepted by match
+// hold keys not recorded in d.known. It does nothing unless unknown keys are
+// disallowed.
+func (d *decoder) checkUnknown(match func(section string) bool) error {
+	if d.known == nil {
+		return nil
+	}
+	var unknown []string
+	for _, section := range d.i.sortedSections() {
+		if !match(section) {
+			continue
+		}
+		for _, k := range sortedKeys(d.i[section]) {
+			if !d.known[section+"\x00"+k] {
+				unknown = append(unknown, section+"."+k)
+			}
+		}
+	}
+	if unknown != nil {
+		return &UnknownKeysError{Keys: unknown}
+	}
+	return nil
+}
+
+func (d *decoder) fieldName(f reflect.StructField) string {
+	if d.opts == nil {
+		return fieldName(f, nil)
+	}
+	return fieldName(f, d.opts.NameMapper)
+}
+
+// fieldName returns the name of the key or section matching a struct field,
+// or an empty string if the field is ignored. names is used for fields
+// without a name in their tag, and can be nil.
+func fieldName(f reflect.StructField, names NameMapper) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag, _, _ := strings.Cut(f.Tag.Get("ini"), ",")
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		if names != nil {
+			return strings.ToLower(names(f.Name))
+		}
+		return strings.ToLower(f.Name)
+	}
+	return strings.ToLower(tag)
+}
+
+// hasOption returns true if the ini tag of a field includes opt, such as
+// "omitempty" in `ini:"name,omitempty"`
+func hasOption(f reflect.StructField, opt string) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("ini"), ",")
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isSection returns true if fields of type t are matched to sections rather
+// than keys
+func isSection(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	p := reflect.PointerTo(t)
+	return !p.Implements(textUnmarshalerType) && !p.Implements(textMarshalerType)
+}
+
+// isSectionField returns true if fields of type t are matched to sections:
+// t is a struct or a pointer to a struct
+func isSectionField(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return isSection(t)
+}
+
+// isEmbedded returns true if the fields of f are promoted to the struct
+// holding it: f is an embedded struct, or pointer to a struct, without a
+// name in its tag
+func isEmbedded(f reflect.StructField) bool {
+	if !f.Anonymous || !isSectionField(f.Type) {
+		return false
+	}
+	tag, _, _ := strings.Cut(f.Tag.Get("ini"), ",")
+	return tag == ""
+}
+
+// subSection returns the name of the section matching a struct field named
+// name found in section: fields of the root section map to sections of the
+// same name, and fields of other sections to dotted sub-sections
+func subSection(section, name string) string {
+	if section == "root" {
+		return name
+	}
+	return section + "." + name
+}
+
+// decodeStruct stores the values of a section in the fields of rv
+func (d *decoder) decodeStruct(section string, rv reflect.Value) error {
+	t := rv.Type()
+	for n := 0; n < t.NumField(); n++ {
+		f := t.Field(n)
+		if isEmbedded(f) {
+			if err := d.decodeNested(section, rv.Field(n)); err != nil {
+				return err
+			}
+			continue
+		}
+		name := d.fieldName(f)
+		if name == "" {
+			continue
+		}
+		if isSectionField(f.Type) {
+			if err := d.decodeNested(subSection(section, name), rv.Field(n)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decodeKey(section, name, f, rv.Field(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeNested decodes a section into fv, a struct or a pointer to a
+// struct. A nil pointer is only set if at least one value was found.
+func (d *decoder) decodeNested(section string, fv reflect.Value) error {
+	if fv.Kind() != reflect.Pointer {
+		return d.decodeStruct(section, fv)
+	}
+	if !fv.IsNil() {
+		return d.decodeStruct(section, fv.Elem())
+	}
+	if !fv.CanSet() {
+		// embedded pointer to an unexported type
+		return nil
+	}
+	// only descend where values can be found, and not twice into the same
+	// type for the same section, so recursive types such as
+	// struct{ Next *Node } terminate
+	key := nilPointer{section, fv.Type()}
+	if !d.hasSection(section) || d.allocating[key] {
+		return nil
+	}
+	if d.allocating == nil {
+		d.allocating = make(map[nilPointer]bool)
+	}
+	d.allocating[key] = true
+	defer delete(d.allocating, key)
+
+	v := reflect.New(fv.Type().Elem())
+	found := d.found
+	if err := d.decodeStruct(section, v.Elem()); err != nil {
+		return err
+	}
+	if d.found != found {
+		fv.Set(v)
+	}
+	return nil
+}
+
+// nilPointer identifies a nil pointer field being decoded
+type nilPointer struct {
+	section string
+	t       reflect.Type
+}
+
+// hasSection returns true if section or one of its sub-sections exists. All
+// sections are sub-sections of root, see subSection.
+func (d *decoder) hasSection(section string) bool {
+	if section == "root" {
+		return len(d.i) > 0
+	}
+	if _, ok := d.i[section]; ok {
+		return true
+	}
+	for n := range d.i {
+		if strings.HasPrefix(n, section+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeKey stores the value of a key, if present, in fv, which is the value
+// of field f
+func (d *decoder) decodeKey(section, key string, f reflect.StructField, fv reflect.Value) error {
+	if d.known != nil {
+		d.known[section+"\x00"+key] = true
+	}
+	v, ok := d.i[section][key]
+	if !ok {
+		return nil
+	}
+	d.found++
+	if err := setValue(fv, v, f.Tag.Get("layout")); err != nil {
+		return &KeyError{Section: section, Key: key, Value: v, Err: err}
+	}
+	return nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// setValue converts s and stores it in fv. layout, if not empty, is used to
+// parse time.Time values, including through pointers and slices.
+func setValue(fv reflect.Value, s, layout string) error {
+	if layout != "" && fv.Type() == timeType {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(fv.Type().Elem())
+		if err := setValue(p.Elem(), s, layout); err != nil {
+			return err
+		}
+		fv.Set(p)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			fv.SetBytes([]byte(s))
+			return nil
+		}
+		var parts []string
+		if s = strings.TrimSpace(s); s != "" {
+			parts = strings.Split(s, ",")
+		}
+		res := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for n, p := range parts {
+			if err := setValue(res.Index(n), strings.TrimSpace(p), layout); err != nil {
+				return err
+			}
+		}
+		fv.Set(res)
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return numError(err)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, fv.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, fv.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+// numError returns the cause of a *strconv.NumError, since the value is
+// already part of KeyError messages
+func numError(err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		return numErr.Err
+	}
+	return err
+}
+
+// UnmarshalKey returns the value of a key converted to T, which can be any
+// type supported by Decode. A missing key causes a *KeyError holding
+// ErrNotFound.
+func UnmarshalKey[T any](i Ini, section, key string) (T, error) {
+	var res T
+	v, ok := i.Get(section, key)
+	if !ok {
+		return res, &KeyError{Section: section, Key: key, Err: ErrNotFound}
+	}
+	if err := setValue(reflect.ValueOf(&res).Elem(), v, ""); err != nil {
+		return res, &KeyError{Section: section, Key: key, Value: v, Err: err}
+	}
+	return res, nil
+}
@@ -0,0 +1,292 @@
+package ini_test
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestDecode(t *testing.T) {
+	type Server struct {
+		Host    string
+		Port    uint16
+		Debug   bool
+		Ratio   float64
+		Ignored string `ini:"-"`
+	}
+	var cfg struct {
+		Name    string
+		Workers int `ini:"worker_count"`
+		Server  Server
+		Admin   Server `ini:"admin_server"`
+		hidden  string
+	}
+	cfg.Server.Host = "default"
+	cfg.Server.Ignored = "kept"
+
+	i := ini.MustParse("name=test\nworker_count=0x10\n[server]\nport=8080\ndebug=true\nratio=0.5\nignored=x\n[admin_server]\nhost=admin\n")
+	if err := i.Decode(&cfg); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+
+	if cfg.Name != "test" || cfg.Workers != 16 || cfg.hidden != "" {
+		t.Errorf("unexpected root values %+v", cfg)
+	}
+	if cfg.Server != (Server{Host: "default", Port: 8080, Debug: true, Ratio: 0.5, Ignored: "kept"}) {
+		t.Errorf("unexpected server %+v", cfg.Server)
+	}
+	if cfg.Admin.Host != "admin" {
+		t.Errorf("unexpected admin server %+v", cfg.Admin)
+	}
+
+	i.Set("server", "port", "70000")
+	err := i.Decode(&cfg)
+	var kerr *ini.KeyError
+	if !errors.As(err, &kerr) || kerr.Section != "server" || kerr.Key != "port" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := i.Decode(cfg); err == nil {
+		t.Errorf("expected an error decoding into a non-pointer")
+	}
+}
+
+func TestDecodePrefix(t *testing.T) {
+	type Upstream struct {
+		URL     string
+		Timeout int
+	}
+
+	i := ini.MustParse("[upstream.api]\nurl=http://api\ntimeout=5\n[upstream.web]\nurl=http://web\n[other]\nurl=x\n")
+
+	m := map[string]Upstream{"web": {Timeout: 30}}
+	if err := i.DecodePrefix("Upstream.", &m); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if len(m) != 2 || m["api"] != (Upstream{"http://api", 5}) || m["web"] != (Upstream{"http://web", 30}) {
+		t.Errorf("unexpected result %v", m)
+	}
+
+	var pm map[string]*Upstream
+	if err := i.DecodePrefix("upstream.", &pm); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if len(pm) != 2 || pm["api"].URL != "http://api" {
+		t.Errorf("unexpected result %v", pm)
+	}
+
+	if err := i.DecodePrefix("upstream.", &map[string]string{}); err == nil {
+		t.Errorf("expected an error for a map of strings")
+	}
+
+	// sub-sections decode into nested fields, not separate entries
+	type TLSUpstream struct {
+		URL string
+		TLS struct {
+			Cert string
+		}
+	}
+	i = ini.MustParse("[upstream.api]\nurl=http://api\n[upstream.api.tls]\ncert=a.pem\n[upstream.web.tls]\ncert=b.pem\n")
+	var tm map[string]TLSUpstream
+	if err := i.DecodePrefixWithOptions("upstream.", &tm, &ini.DecodeOptions{DisallowUnknownKeys: true}); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if len(tm) != 2 || tm["api"].URL != "http://api" || tm["api"].TLS.Cert != "a.pem" || tm["web"].TLS.Cert != "b.pem" {
+		t.Errorf("unexpected result %+v", tm)
+	}
+}
+
+func TestUnmarshalKey(t *testing.T) {
+	i := ini.MustParse("[s]\nports=80, 443,8080\nname=test\nip=192.168.0.1\nbad=1,x\nempty=\n")
+
+	ports, err := ini.UnmarshalKey[[]int](i, "s", "ports")
+	if err != nil || !reflect.DeepEqual(ports, []int{80, 443, 8080}) {
+		t.Errorf("unexpected ports %v, %v", ports, err)
+	}
+	if v, err := ini.UnmarshalKey[string](i, "S", "Name"); err != nil || v != "test" {
+		t.Errorf("unexpected name %q, %v", v, err)
+	}
+	if v, err := ini.UnmarshalKey[netip.Addr](i, "s", "ip"); err != nil || v.String() != "192.168.0.1" {
+		t.Errorf("unexpected address %v, %v", v, err)
+	}
+	if v, err := ini.UnmarshalKey[[]string](i, "s", "empty"); err != nil || len(v) != 0 {
+		t.Errorf("unexpected list %v, %v", v, err)
+	}
+
+	if _, err := ini.UnmarshalKey[[]int](i, "s", "bad"); err == nil {
+		t.Errorf("expected an error for an invalid list")
+	}
+	if _, err := ini.UnmarshalKey[int](i, "s", "missing"); !errors.Is(err, ini.ErrNotFound) {
+		t.Errorf("unexpected error %v for a missing key", err)
+	}
+}
+
+func TestDecodeNested(t *testing.T) {
+	type Common struct {
+		Name string
+	}
+	type TLS struct {
+		Cert string
+		Key  string
+	}
+	type Server struct {
+		Common
+		Port int
+		TLS  TLS
+	}
+	var cfg struct {
+		Common
+		Server Server
+		Named  Common `ini:"named"`
+	}
+
+	src := "name=root\n[named]\nname=named\n[server]\nname=srv\nport=443\n[server.tls]\ncert=a.pem\nkey=a.key\n"
+	if err := ini.MustParse(src).Decode(&cfg); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if cfg.Name != "root" || cfg.Named.Name != "named" || cfg.Server.Name != "srv" || cfg.Server.Port != 443 {
+		t.Errorf("unexpected result %+v", cfg)
+	}
+	if cfg.Server.TLS != (TLS{"a.pem", "a.key"}) {
+		t.Errorf("unexpected tls %+v", cfg.Server.TLS)
+	}
+
+	out, err := ini.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if s := out.String(); s != ini.MustParse(src).String() {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
+
+func TestDecodePointers(t *testing.T) {
+	type Config struct {
+		Port  *int
+		Debug *bool
+		Name  *string
+		Addr  *netip.Addr
+	}
+
+	var cfg Config
+	if err := ini.MustParse("port=0\naddr=::1\n").Decode(&cfg); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if cfg.Port == nil || *cfg.Port != 0 {
+		t.Errorf("expected an explicit zero port, got %v", cfg.Port)
+	}
+	if cfg.Debug != nil || cfg.Name != nil {
+		t.Errorf("expected missing keys to remain nil, got %v %v", cfg.Debug, cfg.Name)
+	}
+	if cfg.Addr == nil || cfg.Addr.String() != "::1" {
+		t.Errorf("unexpected address %v", cfg.Addr)
+	}
+
+	out, err := ini.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if s := out.String(); s != "addr=::1\nport=0\n\n" {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
+
+func TestDecodeUnknownKeys(t *testing.T) {
+	var cfg struct {
+		Name   string
+		Server struct {
+			Port int
+		}
+	}
+
+	i := ini.MustParse("name=test\n[server]\nprotr=8080\n[extra]\nkey=value\n")
+	if err := i.Decode(&cfg); err != nil {
+		t.Errorf("unexpected error without DisallowUnknownKeys: %s", err)
+	}
+
+	err := i.DecodeWithOptions(&cfg, &ini.DecodeOptions{DisallowUnknownKeys: true})
+	var uerr *ini.UnknownKeysError
+	if !errors.As(err, &uerr) || !reflect.DeepEqual(uerr.Keys, []string{"extra.key", "server.protr"}) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if cfg.Name != "test" {
+		t.Errorf("expected known values to be decoded, got %+v", cfg)
+	}
+
+	i.DeleteSection("extra")
+	i.Set("server", "port", "8080")
+	i.Unset("server", "protr")
+	if err := i.DecodeWithOptions(&cfg, &ini.DecodeOptions{DisallowUnknownKeys: true}); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+
+func TestDecodeStructPointers(t *testing.T) {
+	type Common struct {
+		Name string
+	}
+	type TLS struct {
+		Cert string
+	}
+	type Server struct {
+		Port int
+		TLS  *TLS
+	}
+	type Config struct {
+		*Common
+		Server *Server
+		Admin  *Server
+	}
+
+	i := ini.MustParse("name=test\n[server]\nport=80\n[server.tls]\ncert=a.pem\n")
+	var cfg Config
+	if err := i.DecodeWithOptions(&cfg, &ini.DecodeOptions{DisallowUnknownKeys: true}); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if cfg.Common == nil || cfg.Name != "test" {
+		t.Errorf("embedded pointer not decoded: %+v", cfg.Common)
+	}
+	if cfg.Server == nil || cfg.Server.Port != 80 || cfg.Server.TLS == nil || cfg.Server.TLS.Cert != "a.pem" {
+		t.Errorf("unexpected server %+v", cfg.Server)
+	}
+	if cfg.Admin != nil {
+		t.Errorf("expected a nil admin server, got %+v", cfg.Admin)
+	}
+
+	out, err := ini.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if d := out.Diff(i); len(d) != 0 {
+		t.Errorf("unexpected output: %v", d)
+	}
+}
+
+func TestDecodeRecursive(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	i := ini.MustParse("name=a\n[next]\nname=b\n[next.next]\nname=c\n")
+	var n Node
+	if err := i.Decode(&n); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if n.Name != "a" || n.Next == nil || n.Next.Name != "b" || n.Next.Next == nil || n.Next.Next.Name != "c" || n.Next.Next.Next != nil {
+		t.Errorf("unexpected result %+v", n)
+	}
+
+	// an embedded pointer to the same type stays in the same section
+	type Loop struct {
+		Name string
+		*Loop
+	}
+	var l Loop
+	if err := i.Decode(&l); err != nil || l.Name != "a" {
+		t.Errorf("unexpected result %+v %v", l, err)
+	}
+}
@@ -0,0 +1,100 @@
+package ini
+
+import "fmt"
+
+// ChangeKind is the type of a Change.
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Modified
+	Overflow // changes were dropped, see IniSafe.Subscribe
+)
+
+// String returns the name of the kind, such as "added".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	case Overflow:
+		return "overflow"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes the modification of a single value. Old is empty for added
+// values, and New is empty for removed values.
+type Change struct {
+	Kind     ChangeKind
+	Section  string
+	Key      string
+	Old, New string
+}
+
+// String returns a one line description of the change, prefixed with "+",
+// "-" or "~" depending on its kind, or "!" for Overflow.
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s.%s=%s", c.Section, c.Key, c.New)
+	case Removed:
+		return fmt.Sprintf("- %s.%s=%s", c.Section, c.Key, c.Old)
+	case Overflow:
+		return "! changes dropped"
+	default:
+		return fmt.Sprintf("~ %s.%s=%s -> %s", c.Section, c.Key, c.Old, c.New)
+	}
+}
+
+// Diff returns the changes needed to go from i to other, sorted by section
+// and key.
+func (i Ini) Diff(other Ini) []Change {
+	var res []Change
+
+	names := make(map[string]bool)
+	for n := range i {
+		names[n] = true
+	}
+	for n := range other {
+		names[n] = true
+	}
+
+	for _, n := range sortedKeys(names) {
+		res = diffSection(res, n, i[n], other[n])
+	}
+
+	return res
+}
+
+// diffSection appends to res the changes needed to go from a to b, which
+// are the contents of the named section
+func diffSection(res []Change, section string, a, b map[string]string) []Change {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	for _, k := range sortedKeys(keys) {
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inA:
+			res = append(res, Change{Kind: Added, Section: section, Key: k, New: vb})
+		case !inB:
+			res = append(res, Change{Kind: Removed, Section: section, Key: k, Old: va})
+		case va != vb:
+			res = append(res, Change{Kind: Modified, Section: section, Key: k, Old: va, New: vb})
+		}
+	}
+
+	return res
+}
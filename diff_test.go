@@ -0,0 +1,32 @@
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestDiff(t *testing.T) {
+	a, b := ini.New(), ini.New()
+	a.Load(strings.NewReader("var1=value1\nvar2=value2\n[section]\nvar3=value3\n"))
+	b.Load(strings.NewReader("var1=value1\nvar2=changed\n[other]\nvar4=value4\n"))
+
+	var res []string
+	for _, c := range a.Diff(b) {
+		res = append(res, c.String())
+	}
+
+	expect := []string{
+		"+ other.var4=value4",
+		"~ root.var2=value2 -> changed",
+		"- section.var3=value3",
+	}
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected diff:\n%s", strings.Join(res, "\n"))
+	}
+
+	if d := a.Diff(a); len(d) != 0 {
+		t.Errorf("expected no difference, got %v", d)
+	}
+}
@@ -0,0 +1,41 @@
+package ini
+
+// IsDirty returns true if values were modified since data was last loaded
+// with Reload, or saved with SaveFile. Load and ReadFrom merge values, so
+// they only keep the data clean if it was clean before.
+func (i *IniSafe) IsDirty() bool {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	return i.gen != i.savedGen
+}
+
+// SaveFile writes the data to the file at path atomically (see Ini.SaveFile)
+// and marks it as clean.
+func (i *IniSafe) SaveFile(path string) error {
+	i.lk.Lock()
+	data, gen := i.snapshot(), i.gen
+	i.lk.Unlock()
+
+	if err := data.SaveFile(path); err != nil {
+		return err
+	}
+
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	// changes made while saving keep the data dirty
+	if gen > i.savedGen {
+		i.savedGen = gen
+	}
+	return nil
+}
+
+// SaveIfDirty calls SaveFile only if values were modified since they were
+// last loaded or saved.
+func (i *IniSafe) SaveIfDirty(path string) error {
+	if !i.IsDirty() {
+		return nil
+	}
+	return i.SaveFile(path)
+}
@@ -0,0 +1,60 @@
+package ini_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafeDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ini")
+
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("[db]\nhost=a\n"))
+	if i.IsDirty() {
+		t.Errorf("data is dirty after load")
+	}
+
+	i.Set("db", "host", "a")
+	if i.IsDirty() {
+		t.Errorf("data is dirty after setting the same value")
+	}
+	if err := i.SaveIfDirty(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("clean data was saved")
+	}
+
+	i.Set("db", "host", "b")
+	if !i.IsDirty() {
+		t.Errorf("data is not dirty after change")
+	}
+	if err := i.SaveIfDirty(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	if i.IsDirty() {
+		t.Errorf("data is dirty after save")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("dirty data was not saved: %s", err)
+	}
+
+	// a merging load keeps unsaved changes dirty
+	i.Set("db", "host", "c")
+	i.Load(strings.NewReader("[db]\nport=1\n"))
+	if !i.IsDirty() {
+		t.Errorf("unsaved changes are not dirty after load")
+	}
+
+	// reload replaces all values with the file content
+	if _, err := i.Reload(strings.NewReader("[db]\nhost=d\n")); err != nil {
+		t.Fatalf("failed to reload: %s", err)
+	}
+	if i.IsDirty() {
+		t.Errorf("data is dirty after reload")
+	}
+}
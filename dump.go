@@ -0,0 +1,30 @@
+package ini
+
+import (
+	"io"
+	"text/tabwriter"
+)
+
+// DumpTo writes a human readable table of all values to w, with one line per
+// value showing its section, key and value, sorted like Write. Values are
+// quoted when needed so surrounding spaces remain visible. It is meant for
+// debugging, such as a --dump-config flag; use i.Redacted().DumpTo to hide
+// secrets.
+func (i Ini) DumpTo(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	io.WriteString(tw, "SECTION\tKEY\tVALUE\n")
+
+	for _, n := range i.sortedSections() {
+		s := i[n]
+		for _, k := range sortedKeys(s) {
+			io.WriteString(tw, n+"\t"+k+"\t"+quoteValue(s[k])+"\n")
+		}
+	}
+
+	return tw.Flush()
+}
+
+// DumpTo writes a table of all values to w, see Ini.DumpTo.
+func (i *IniSafe) DumpTo(w io.Writer) error {
+	return i.snapshot().DumpTo(w)
+}
@@ -0,0 +1,24 @@
+package ini_test
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpTo(t *testing.T) {
+	i := mustParseUnquote("name=test\n[server]\nhost=localhost\nlisten=\" :80\"\n[a]\nb=c\n")
+
+	buf := &bytes.Buffer{}
+	if err := i.DumpTo(buf); err != nil {
+		t.Fatalf("failed to dump: %s", err)
+	}
+
+	expect := "SECTION  KEY     VALUE\n" +
+		"root     name    test\n" +
+		"a        b       c\n" +
+		"server   host    localhost\n" +
+		"server   listen  \" :80\"\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected dump:\n%s\nexpected:\n%s", buf, expect)
+	}
+}
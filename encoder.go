@@ -0,0 +1,176 @@
+package ini
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Encoder writes ini data incrementally, so large files can be generated
+// without building an Ini first. Output is buffered: Close must be called
+// once done.
+//
+// Values written before the first call to WriteSection belong to the root
+// section. Errors are sticky: once a write failed, all following calls
+// return the same error.
+type Encoder struct {
+	w    *bufio.Writer
+	err  error
+	opts *WriteOptions
+	last byte // kind of the last line written: 0 (none), 's'ection, 'k'ey or 'c'omment
+}
+
+// errEncoderClosed is returned when using an Encoder after Close
+var errEncoderClosed = errors.New("ini: encoder is closed")
+
+// NewEncoder returns a new Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return NewEncoderWithOptions(w, nil)
+}
+
+// NewEncoderWithOptions returns a new Encoder writing to w using the given
+// options. opts can be nil. Align, SectionLess and KeyLess do not apply to
+// an Encoder, since it does not know what will be written next.
+func NewEncoderWithOptions(w io.Writer, opts *WriteOptions) *Encoder {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	return &Encoder{w: getWriter(w), opts: opts}
+}
+
+func (e *Encoder) write(s ...string) error {
+	if e.err != nil {
+		return e.err
+	}
+	for _, v := range s {
+		if _, err := e.w.WriteString(v); err != nil {
+			e.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// blank writes n blank lines
+func (e *Encoder) blank(n int) error {
+	if n <= 0 {
+		return e.err
+	}
+	return e.write(strings.Repeat("\n", n))
+}
+
+// WriteSection starts a new section. Blank lines (one by default) separate
+// it from what was written before.
+func (e *Encoder) WriteSection(name string) error {
+	return e.writeSection(name, nil)
+}
+
+// writeSection starts a new section, with comment lines above its header
+func (e *Encoder) writeSection(name string, comment []string) error {
+	if e.last != 0 {
+		if err := e.blank(e.opts.sectionBlankLines()); err != nil {
+			return err
+		}
+	}
+	e.last = 's'
+	for _, l := range comment {
+		if err := e.write(l, "\n"); err != nil {
+			return err
+		}
+	}
+	return e.write("[", name, "]\n")
+}
+
+// WriteKey writes a value in the current section, quoted according to
+// WriteOptions.Quote.
+func (e *Encoder) WriteKey(key, value string) error {
+	return e.writeKey(key, value, 0)
+}
+
+// writeKey writes a value with the key padded to width
+func (e *Encoder) writeKey(key, value string, width int) error {
+	if e.err != nil {
+		return e.err
+	}
+	value, err := quoteStyle(value, e.opts.Quote, e.opts.ASCII)
+	if err != nil {
+		e.err = fmt.Errorf("failed to write key %s: %w", key, err)
+		return e.err
+	}
+	delim := e.opts.Spacing.delimiter()
+	e.last = 'k'
+	if pad := width - len(key); pad > 0 {
+		return e.write(key, strings.Repeat(" ", pad), delim, value, "\n")
+	}
+	return e.write(key, delim, value, "\n")
+}
+
+// WriteComment writes a comment. Each line of text becomes a separate
+// comment line.
+func (e *Encoder) WriteComment(text string) error {
+	lines := commentLines(text)
+	if lines == nil {
+		lines = []string{";"}
+	}
+	return e.writeComment(lines)
+}
+
+// writeComment writes comment lines as is
+func (e *Encoder) writeComment(lines []string) error {
+	if len(lines) == 0 {
+		return e.err
+	}
+	if e.last == 'k' && e.opts.BlankBeforeComment {
+		if err := e.blank(1); err != nil {
+			return err
+		}
+	}
+	e.last = 'c'
+	for _, l := range lines {
+		if err := e.write(l, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeHeader writes the comment at the top of the file, followed by a blank
+// line
+func (e *Encoder) writeHeader(lines []string) error {
+	if len(lines) == 0 {
+		return e.err
+	}
+	if err := e.writeComment(lines); err != nil {
+		return err
+	}
+	e.last = 0
+	return e.blank(1)
+}
+
+// Flush writes buffered data to the underlying writer.
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = e.w.Flush()
+	return e.err
+}
+
+// Close terminates the last section and flushes buffered data. It does not
+// close the underlying writer. The Encoder cannot be used after Close.
+func (e *Encoder) Close() error {
+	if e.err == errEncoderClosed {
+		return e.err
+	}
+	if e.last != 0 && !e.opts.NoTrailingBlankLine {
+		e.blank(1)
+	}
+	err := e.Flush()
+
+	putWriter(e.w)
+	e.w = nil
+	e.err = errEncoderClosed
+	return err
+}
@@ -0,0 +1,61 @@
+package ini_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := ini.NewEncoderWithOptions(buf, &ini.WriteOptions{Quote: ini.QuoteMinimal})
+
+	enc.WriteComment("generated file\ndo not edit")
+	enc.WriteKey("var1", "value1")
+	enc.WriteSection("section")
+	enc.WriteKey("var2", " padded ")
+	if err := enc.Close(); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "; generated file\n; do not edit\nvar1=value1\n\n[section]\nvar2=\" padded \"\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	i := mustParseUnquote(buf.String())
+	if v, _ := i.Get("section", "var2"); v != " padded " {
+		t.Errorf("unexpected value %#v", v)
+	}
+}
+
+func TestEncoderBlankLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := ini.NewEncoderWithOptions(buf, &ini.WriteOptions{BlankLines: 2, BlankBeforeComment: true, NoTrailingBlankLine: true})
+
+	enc.WriteComment("header")
+	enc.WriteKey("a", "1")
+	enc.WriteComment("about b")
+	enc.WriteKey("b", "2")
+	enc.WriteSection("s")
+	enc.WriteComment("about c")
+	enc.WriteKey("c", "3")
+	if err := enc.Close(); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "; header\na=1\n\n; about b\nb=2\n\n\n[s]\n; about c\nc=3\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	buf.Reset()
+	i := ini.MustParse("a=1\n[s]\nb=2\n")
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{BlankLines: -1}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	if buf.String() != "a=1\n[s]\nb=2\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
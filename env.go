@@ -0,0 +1,107 @@
+package ini
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// EnvMapper returns the section and key overridden by an environment
+// variable, given its name with the prefix removed. An empty key skips the
+// variable.
+type EnvMapper func(name string) (section, key string)
+
+// ApplyEnvOverrides scans the process environment for variables named
+// PREFIX_SECTION_KEY and stores their value in the ini, so deployments can
+// override settings without editing files. Variables named PREFIX_KEY are
+// stored in the root section. The prefix cannot be empty, as every variable
+// of the environment would then be stored.
+//
+// If mapper is nil, names are matched against existing sections and keys
+// first (ignoring case and treating any non-alphanumeric character as an
+// underscore), so a variable such as APP_HTTP_SERVER_MAX_CONN overrides
+// max-conn in section http.server. Other names are split on their first
+// underscore. All names are mapped before any value is stored, so the
+// result does not depend on the order of the environment.
+func (i Ini) ApplyEnvOverrides(prefix string, mapper EnvMapper) error {
+	if prefix == "" {
+		return errors.New("ini: environment prefix cannot be empty")
+	}
+	prefix = strings.ToUpper(prefix) + "_"
+	if mapper == nil {
+		mapper = i.mapEnv
+	}
+
+	var overrides [][3]string
+	for _, e := range os.Environ() {
+		pos := strings.IndexByte(e, '=')
+		if pos < 0 || !strings.HasPrefix(e[:pos], prefix) {
+			continue
+		}
+
+		section, key := mapper(e[len(prefix):pos])
+		if key == "" {
+			continue
+		}
+		overrides = append(overrides, [3]string{section, key, e[pos+1:]})
+	}
+
+	for _, o := range overrides {
+		i.Set(o[0], o[1], o[2])
+	}
+	return nil
+}
+
+func (i Ini) mapEnv(name string) (string, string) {
+	name = strings.ToUpper(name)
+
+	section := ""
+	for n := range i {
+		if n != "root" && strings.HasPrefix(name, envName(n)+"_") && len(n) > len(section) {
+			section = n
+		}
+	}
+
+	rest := name
+	switch {
+	case section != "":
+		rest = name[len(envName(section))+1:]
+	case i.findEnvKey("root", name) != "":
+		section = "root"
+	case strings.IndexByte(name, '_') > 0:
+		pos := strings.IndexByte(name, '_')
+		section, rest = strings.ToLower(name[:pos]), name[pos+1:]
+	default:
+		section = "root"
+	}
+
+	if k := i.findEnvKey(section, rest); k != "" {
+		return section, k
+	}
+	return section, strings.ToLower(rest)
+}
+
+// findEnvKey returns the key of section matching the environment variable
+// name, if any
+func (i Ini) findEnvKey(section, name string) string {
+	for k := range i[section] {
+		if envName(k) == name {
+			return k
+		}
+	}
+	return ""
+}
+
+// envName returns name converted to the form used in environment variables
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
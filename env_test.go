@@ -0,0 +1,42 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	i := ini.New()
+	i.Set("http.server", "max-conn", "10")
+	i.Set("root", "log_level", "info")
+
+	t.Setenv("APP_HTTP_SERVER_MAX_CONN", "20")
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("APP_DB_HOST", "localhost")
+	t.Setenv("APP_DEBUG", "1")
+
+	t.Setenv("APP_DB_HOST_NAME", "db1")
+
+	if err := i.ApplyEnvOverrides("app", nil); err != nil {
+		t.Fatalf("failed to apply overrides: %s", err)
+	}
+
+	expect := [][3]string{
+		{"http.server", "max-conn", "20"},
+		{"root", "log_level", "debug"},
+		{"db", "host", "localhost"},
+		{"db", "host_name", "db1"},
+
+		{"root", "debug", "1"},
+	}
+	for _, e := range expect {
+		if v, ok := i.Get(e[0], e[1]); !ok || v != e[2] {
+			t.Errorf("failed to get value %s/%s, read %#v %#v", e[0], e[1], v, ok)
+		}
+	}
+
+	if err := i.ApplyEnvOverrides("", nil); err == nil {
+		t.Errorf("expected an error for an empty prefix")
+	}
+}
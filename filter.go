@@ -0,0 +1,50 @@
+package ini
+
+import "strings"
+
+// Filter returns a new Ini holding only the values for which fn returns true.
+// Sections without any remaining value are omitted.
+func (i Ini) Filter(fn func(section, key, value string) bool) Ini {
+	res := New()
+
+	for n, s := range i {
+		var sub map[string]string
+		for k, v := range s {
+			if !fn(n, k, v) {
+				continue
+			}
+			if sub == nil {
+				sub = make(map[string]string)
+				res[n] = sub
+			}
+			sub[k] = v
+		}
+	}
+
+	return res
+}
+
+// Subset returns a new Ini holding a copy of the named sections. Missing
+// sections are ignored.
+func (i Ini) Subset(sections ...string) Ini {
+	res := New()
+
+	for _, n := range sections {
+		if s := i.SectionMap(n); s != nil {
+			res[strings.ToLower(n)] = s
+		}
+	}
+
+	return res
+}
+
+// Transform replaces every value with the result of fn, such as to rewrite
+// paths or trim values. Use IniSafe.Update to transform an IniSafe in a
+// single step.
+func (i Ini) Transform(fn func(section, key, value string) string) {
+	for n, s := range i {
+		for k, v := range s {
+			s[k] = fn(n, k, v)
+		}
+	}
+}
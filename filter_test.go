@@ -0,0 +1,55 @@
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestFilter(t *testing.T) {
+	i := ini.MustParse("name=test\n[db]\nhost=localhost\npassword=secret\n[api]\ntoken=abc\n")
+
+	res := i.Filter(func(section, key, value string) bool {
+		return !ini.IsSecretKey(key)
+	})
+
+	expect := ini.MustParse("name=test\n[db]\nhost=localhost\n")
+	if d := res.Diff(expect); len(d) != 0 {
+		t.Errorf("unexpected result: %v", d)
+	}
+	if _, ok := res["api"]; ok {
+		t.Errorf("empty section not omitted")
+	}
+	if v, _ := i.Get("db", "password"); v != "secret" {
+		t.Errorf("source was modified")
+	}
+}
+
+func TestSubset(t *testing.T) {
+	i := ini.MustParse("name=test\n[db]\nhost=localhost\n[api]\nurl=http://localhost\n[cache]\nsize=10\n")
+
+	res := i.Subset("DB", "cache", "missing")
+	expect := ini.MustParse("[db]\nhost=localhost\n[cache]\nsize=10\n")
+	if d := res.Diff(expect); len(d) != 0 {
+		t.Errorf("unexpected result: %v", d)
+	}
+
+	res.Set("db", "host", "other")
+	if v, _ := i.Get("db", "host"); v != "localhost" {
+		t.Errorf("source was modified")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	i := ini.MustParse("path=/old/data\n[log]\nfile=/old/log/app.log\nlevel=info\n")
+
+	i.Transform(func(section, key, value string) string {
+		return strings.Replace(value, "/old/", "/new/", 1)
+	})
+
+	expect := ini.MustParse("path=/new/data\n[log]\nfile=/new/log/app.log\nlevel=info\n")
+	if d := i.Diff(expect); len(d) != 0 {
+		t.Errorf("unexpected result: %v", d)
+	}
+}
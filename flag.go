@@ -0,0 +1,38 @@
+package ini
+
+import (
+	"flag"
+	"fmt"
+)
+
+// BindFlags sets the default value of each flag in fs from the key with the
+// same name in section. It should be called before fs.Parse so that command
+// line arguments take precedence over the ini file.
+func (i Ini) BindFlags(fs *flag.FlagSet, section string) error {
+	var err error
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		v, ok := i.Get(section, f.Name)
+		if !ok {
+			return
+		}
+		if e := f.Value.Set(v); e != nil {
+			err = fmt.Errorf("invalid value for %s.%s: %w", section, f.Name, e)
+			return
+		}
+		f.DefValue = v
+	})
+
+	return err
+}
+
+// SaveFlags stores the value of each flag that was set on the command line
+// into section, so changes can be persisted by writing the ini.
+func (i Ini) SaveFlags(fs *flag.FlagSet, section string) {
+	fs.Visit(func(f *flag.Flag) {
+		i.Set(section, f.Name, f.Value.String())
+	})
+}
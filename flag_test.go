@@ -0,0 +1,33 @@
+package ini_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestBindFlags(t *testing.T) {
+	i := ini.New()
+	i.Set("app", "port", "8080")
+	i.Set("app", "host", "localhost")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	port := fs.Int("port", 80, "listen port")
+	host := fs.String("host", "", "listen host")
+
+	if err := i.BindFlags(fs, "app"); err != nil {
+		t.Fatalf("failed to bind flags: %s", err)
+	}
+	if err := fs.Parse([]string{"-host", "example.com"}); err != nil {
+		t.Fatalf("failed to parse flags: %s", err)
+	}
+	if *port != 8080 || *host != "example.com" {
+		t.Errorf("unexpected flag values %d %s", *port, *host)
+	}
+
+	i.SaveFlags(fs, "app")
+	if v, _ := i.Get("app", "host"); v != "example.com" {
+		t.Errorf("flag was not saved, got %#v", v)
+	}
+}
@@ -0,0 +1,39 @@
+package ini
+
+import "strings"
+
+// Flatten returns all values in a single map using "section.key" as keys.
+// Entries of the root section are stored using only their key.
+func (i Ini) Flatten() map[string]string {
+	res := make(map[string]string)
+
+	for n, s := range i {
+		for k, v := range s {
+			if n == "root" {
+				res[k] = v
+			} else {
+				res[n+"."+k] = v
+			}
+		}
+	}
+
+	return res
+}
+
+// Unflatten is the reverse of Flatten. Names are split on their last dot, so
+// sections may contain dots but keys may not. Names without a dot are stored
+// in the root section.
+func Unflatten(m map[string]string) Ini {
+	res := New()
+
+	for n, v := range m {
+		pos := strings.LastIndexByte(n, '.')
+		if pos < 0 {
+			res.Set("root", n, v)
+			continue
+		}
+		res.Set(n[:pos], n[pos+1:], v)
+	}
+
+	return res
+}
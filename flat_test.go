@@ -0,0 +1,29 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestFlatten(t *testing.T) {
+	src := ini.New()
+	src.Set("root", "var1", "value1")
+	src.Set("server.http", "port", "8080")
+
+	flat := src.Flatten()
+	if v := flat["var1"]; v != "value1" {
+		t.Errorf("invalid flattened value for var1, got %#v", v)
+	}
+	if v := flat["server.http.port"]; v != "8080" {
+		t.Errorf("invalid flattened value for server.http.port, got %#v", v)
+	}
+
+	res := ini.Unflatten(flat)
+	if v, ok := res.Get("server.http", "port"); !ok || v != "8080" {
+		t.Errorf("failed to get value server.http/port, read %#v %#v", v, ok)
+	}
+	if v, ok := res.Get("root", "var1"); !ok || v != "value1" {
+		t.Errorf("failed to get value root/var1, read %#v %#v", v, ok)
+	}
+}
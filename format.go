@@ -0,0 +1,195 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FormatOptions holds settings used by Format.
+type FormatOptions struct {
+	// Sort causes sections and keys to be sorted alphabetically. Comments
+	// move along with the section or key that follows them.
+	Sort bool
+
+	// Spacing controls spaces around '='. With SpacingPreserve, the spacing
+	// of each line is kept as is.
+	Spacing Spacing
+
+	// Unquote causes values to be read like LoadOptions.Unquote does, and
+	// written back with double quotes only when needed, so `a="foo"`
+	// becomes a=foo. By default values are kept as found, so the output
+	// loads to the same values as the input.
+	Unquote bool
+}
+
+type formatEntry struct {
+	comments []string
+	blank    bool // preceded by a blank line
+	key      string
+	delim    string // original delimiter and surrounding spaces
+	value    string
+}
+
+type formatSection struct {
+	comments []string
+	name     string // empty for the root section
+	entries  []*formatEntry
+	trailing []string // comments at the end of the section
+}
+
+// Format reads ini data from src and writes it to dst in canonical form:
+// lines are trimmed, keys and values are separated by a single '=' without
+// spaces, sections are separated by one blank line and runs of blank lines
+// are collapsed. Unlike Load and Write,
+// comments and the order of entries are kept.
+func Format(dst io.Writer, src io.Reader, opts *FormatOptions) error {
+	unquote := opts != nil && opts.Unquote
+
+	root := &formatSection{}
+	sections := []*formatSection{root}
+	cur := root
+	var comments []string
+	blank := false // blank line seen before the pending comments or entry
+
+	r := bufio.NewScanner(src)
+	lineNo := 0
+	for r.Scan() {
+		lineNo++
+		line := strings.TrimSpace(r.Text())
+		switch {
+		case line == "":
+			if len(comments) == 0 {
+				blank = true
+			} else if cur == root && len(sections) == 1 && len(root.entries) == 0 && root.comments == nil {
+				// comment block at the top of the file, detached from
+				// what follows
+				root.comments = comments
+				comments = nil
+			} else if comments[len(comments)-1] != "" {
+				comments = append(comments, "")
+			}
+		case line[0] == ';':
+			comments = append(comments, line)
+		case line[0] == '[' && line[len(line)-1] == ']':
+			cur = &formatSection{comments: comments, name: strings.TrimSpace(line[1 : len(line)-1])}
+			sections = append(sections, cur)
+			comments = nil
+			blank = false
+		default:
+			pos := strings.IndexByte(line, '=')
+			if pos < 0 {
+				return fmt.Errorf("failed to parse ini file on line %d: invalid line", lineNo)
+			}
+			key := strings.TrimSpace(line[:pos])
+			raw := strings.TrimSpace(line[pos+1:])
+			v := raw
+			if unquote {
+				v = quoteValue(unquoteValue(raw))
+			}
+			e := &formatEntry{comments: comments, blank: blank && len(cur.entries) > 0, key: key, delim: line[len(key) : len(line)-len(raw)], value: v}
+			cur.entries = append(cur.entries, e)
+			comments = nil
+			blank = false
+		}
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+	for len(comments) > 0 && comments[len(comments)-1] == "" {
+		comments = comments[:len(comments)-1]
+	}
+	cur.trailing = comments
+
+	if opts != nil && opts.Sort {
+		rest := sections[1:]
+		sort.SliceStable(rest, func(a, b int) bool {
+			return strings.ToLower(rest[a].name) < strings.ToLower(rest[b].name)
+		})
+		for _, s := range sections {
+			sort.SliceStable(s.entries, func(a, b int) bool {
+				return strings.ToLower(s.entries[a].key) < strings.ToLower(s.entries[b].key)
+			})
+			for _, e := range s.entries {
+				e.blank = false
+			}
+		}
+	}
+
+	var spacing Spacing
+	if opts != nil {
+		spacing = opts.Spacing
+	}
+
+	w := bufio.NewWriter(dst)
+	first := true
+	// block starts a new block, separated from the previous one by a blank
+	// line
+	block := func() {
+		if !first {
+			w.WriteByte('\n')
+		}
+		first = false
+	}
+	writeComments := func(c []string) {
+		for _, l := range c {
+			w.WriteString(l)
+			w.WriteByte('\n')
+		}
+	}
+
+	for _, s := range sections {
+		if s == root {
+			if len(s.comments) > 0 {
+				block()
+				writeComments(s.comments)
+			}
+			if len(s.entries) > 0 || len(s.trailing) > 0 {
+				block()
+			}
+		} else {
+			block()
+			writeComments(s.comments)
+			w.WriteString("[" + s.name + "]\n")
+		}
+		for _, e := range s.entries {
+			if e.blank {
+				w.WriteByte('\n')
+			}
+			writeComments(e.comments)
+			delim := e.delim
+			if spacing != SpacingPreserve {
+				delim = spacing.delimiter()
+			}
+			w.WriteString(e.key + delim + e.value + "\n")
+		}
+		writeComments(s.trailing)
+	}
+
+	return w.Flush()
+}
+
+// FormatFile formats the file at path like Format and replaces it if its
+// formatting changed, like SaveFile does. It returns true if the file was
+// changed.
+func FormatFile(path string, opts *FormatOptions) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	buf := &bytes.Buffer{}
+	if err := Format(buf, bytes.NewReader(src), opts); err != nil {
+		return false, err
+	}
+	if bytes.Equal(src, buf.Bytes()) {
+		return false, nil
+	}
+	return true, writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
@@ -0,0 +1,125 @@
+package ini_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestFormat(t *testing.T) {
+	src := `; header comment
+
+
+  var2 =  value2
+var1="value1"
+
+[ section ]
+; about b
+b = "  padded  "
+
+
+a=1
+; trailing
+`
+
+	expect := `; header comment
+
+var2=value2
+var1="value1"
+
+[section]
+; about b
+b="  padded  "
+
+a=1
+; trailing
+`
+
+	buf := &bytes.Buffer{}
+	if err := ini.Format(buf, strings.NewReader(src), nil); err != nil {
+		t.Fatalf("failed to format: %s", err)
+	}
+	if buf.String() != expect {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	expect = `; header comment
+
+var1="value1"
+var2=value2
+
+[section]
+a=1
+; about b
+b="  padded  "
+; trailing
+`
+
+	buf.Reset()
+	if err := ini.Format(buf, strings.NewReader(src), &ini.FormatOptions{Sort: true}); err != nil {
+		t.Fatalf("failed to format: %s", err)
+	}
+	if buf.String() != expect {
+		t.Errorf("unexpected sorted output:\n%s", buf.String())
+	}
+}
+
+func TestFormatSpacing(t *testing.T) {
+	src := "a = 1\nb=2\nc  =\t3\n"
+
+	for spacing, expect := range map[ini.Spacing]string{
+		ini.SpacingNone:     "a=1\nb=2\nc=3\n",
+		ini.SpacingAround:   "a = 1\nb = 2\nc = 3\n",
+		ini.SpacingPreserve: "a = 1\nb=2\nc  =\t3\n",
+	} {
+		buf := &bytes.Buffer{}
+		if err := ini.Format(buf, strings.NewReader(src), &ini.FormatOptions{Spacing: spacing}); err != nil {
+			t.Fatalf("failed to format: %s", err)
+		}
+		if buf.String() != expect {
+			t.Errorf("unexpected output for spacing %d: %q", spacing, buf.String())
+		}
+	}
+}
+
+func TestFormatQuotes(t *testing.T) {
+	src := "a = \"foo\"\np=\"C:\\xyz\"\nwin=C:\\temp\\new\nb=\" padded \"\n"
+
+	// values are kept as found, so a plain Load reads the same values
+	buf := &bytes.Buffer{}
+	if err := ini.Format(buf, strings.NewReader(src), nil); err != nil {
+		t.Fatalf("failed to format: %s", err)
+	}
+	if s := buf.String(); s != "a=\"foo\"\np=\"C:\\xyz\"\nwin=C:\\temp\\new\nb=\" padded \"\n" {
+		t.Errorf("unexpected output %q", s)
+	}
+	if d := ini.MustParse(src).Diff(ini.MustParse(buf.String())); len(d) != 0 {
+		t.Errorf("values changed: %v", d)
+	}
+
+	buf.Reset()
+	if err := ini.Format(buf, strings.NewReader(src), &ini.FormatOptions{Unquote: true}); err != nil {
+		t.Fatalf("failed to format: %s", err)
+	}
+	if s := buf.String(); s != "a=foo\np=C:\\xyz\nwin=C:\\temp\\new\nb=\" padded \"\n" {
+		t.Errorf("unexpected unquoted output %q", s)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.ini")
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := ini.FormatFile(path, nil); err != nil || !changed {
+		t.Fatalf("unexpected result %v %v", changed, err)
+	}
+	if changed, err := ini.FormatFile(path, nil); err != nil || changed {
+		t.Errorf("file changed when formatted twice: %v %v", changed, err)
+	}
+	if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0600 {
+		t.Errorf("permissions not kept")
+	}
+}
@@ -0,0 +1,65 @@
+package ini
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrReadOnly is returned when attempting to modify read-only data.
+var ErrReadOnly = errors.New("ini is read-only")
+
+// Frozen is a read-only copy of an Ini. Its Set and Unset methods always
+// return ErrReadOnly, so it can be handed to code that must not modify the
+// configuration. It is safe for concurrent use.
+type Frozen struct {
+	data Ini
+}
+
+// Freeze returns a read-only copy of i. Later changes to i do not affect the
+// returned value.
+func (i Ini) Freeze() *Frozen {
+	return &Frozen{data: i.clone()}
+}
+
+// clone returns a deep copy of i
+func (i Ini) clone() Ini {
+	res := make(Ini, len(i))
+	for n, s := range i {
+		sub := make(map[string]string, len(s))
+		for k, v := range s {
+			sub[k] = v
+		}
+		res[n] = sub
+	}
+	return res
+}
+
+// Get returns a value for a given key.
+func (f *Frozen) Get(section, key string) (string, bool) {
+	return f.data.Get(section, key)
+}
+
+// Set always returns ErrReadOnly.
+func (f *Frozen) Set(section, key, value string) error {
+	return ErrReadOnly
+}
+
+// Unset always returns ErrReadOnly.
+func (f *Frozen) Unset(section, key string) error {
+	return ErrReadOnly
+}
+
+// Ini returns a modifiable copy of the frozen data.
+func (f *Frozen) Ini() Ini {
+	return f.data.clone()
+}
+
+// Write generates a ini file and writes it to the provided output.
+func (f *Frozen) Write(d io.Writer) error {
+	return f.data.Write(d)
+}
+
+// WriteTo implements io.WriterTo.
+func (f *Frozen) WriteTo(w io.Writer) (int64, error) {
+	return f.data.WriteTo(w)
+}
@@ -0,0 +1,23 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestFreeze(t *testing.T) {
+	i := ini.MustParse("[section]\nvar1=value1")
+	f := i.Freeze()
+	i.Set("section", "var1", "changed")
+
+	if v, ok := f.Get("section", "var1"); !ok || v != "value1" {
+		t.Errorf("failed to get value section/var1, read %#v %#v", v, ok)
+	}
+	if err := f.Set("section", "var1", "x"); err != ini.ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if err := f.Unset("section", "var1"); err != ini.ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package ini
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hash returns a hex encoded SHA-256 digest of the values. Since sections and
+// keys are written in a fixed order, two Ini holding the same values always
+// have the same hash, which can be used to detect changes or as a HTTP ETag.
+func (i Ini) Hash() string {
+	h := sha256.New()
+	i.Write(h) // hash.Hash never returns errors
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Hash returns a digest of the current values, see Ini.Hash.
+func (i *IniSafe) Hash() string {
+	return i.snapshot().Hash()
+}
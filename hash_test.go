@@ -0,0 +1,21 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestHash(t *testing.T) {
+	a := ini.MustParse("var1=value1\n[B]\nx=1\n[a]\ny=2\n")
+	b := ini.MustParse("[a]\ny = 2\n[b]\nX=1\n[root]\nvar1=value1\n")
+
+	if a.Hash() != b.Hash() {
+		t.Errorf("identical values have different hashes")
+	}
+
+	b.Set("a", "y", "3")
+	if a.Hash() == b.Hash() {
+		t.Errorf("different values have the same hash")
+	}
+}
@@ -0,0 +1,94 @@
+package ini
+
+// EnableHistory keeps up to max previous versions of the data so changes can
+// be reverted with Undo. A max of zero disables history and forgets all
+// recorded versions.
+func (i *IniSafe) EnableHistory(max int) {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	i.historyMax = max
+	if max <= 0 {
+		i.undo, i.redo = nil, nil
+		return
+	}
+	if len(i.undo) > max {
+		i.undo = i.undo[len(i.undo)-max:]
+	}
+	if len(i.redo) > max {
+		i.redo = i.redo[len(i.redo)-max:]
+	}
+}
+
+// record adds old to the undo history, and clears the redo history. The
+// caller must hold i.lk.
+func (i *IniSafe) record(old Ini) {
+	if i.historyMax <= 0 {
+		return
+	}
+	i.undo = append(i.undo, old)
+	if len(i.undo) > i.historyMax {
+		i.undo = i.undo[1:]
+	}
+	i.redo = nil
+}
+
+// Undo reverts the last change (a call to Set, Unset, Update, Load...) and
+// returns true, or returns false if there is nothing to undo. Sections
+// currently read-only are left unchanged. History must
+// have been enabled with EnableHistory.
+func (i *IniSafe) Undo() bool {
+	i.lockAll()
+	defer i.unlockAll()
+
+	if len(i.undo) == 0 {
+		return false
+	}
+	prev := i.undo[len(i.undo)-1]
+	i.undo = i.undo[:len(i.undo)-1]
+
+	cur := i.snapshot()
+	i.replace(cur, i.keepReadOnly(cur, prev), "")
+	i.redo = append(i.redo, cur)
+	return true
+}
+
+// Redo applies again the last change reverted by Undo and returns true, or
+// returns false if there is nothing to redo. Any change made after Undo
+// clears the redo history.
+func (i *IniSafe) Redo() bool {
+	i.lockAll()
+	defer i.unlockAll()
+
+	if len(i.redo) == 0 {
+		return false
+	}
+	next := i.redo[len(i.redo)-1]
+	i.redo = i.redo[:len(i.redo)-1]
+
+	cur := i.snapshot()
+	i.replace(cur, i.keepReadOnly(cur, next), "")
+	i.undo = append(i.undo, cur)
+	return true
+}
+
+// keepReadOnly returns data with read-only sections set to their content in
+// cur, so restoring a version never modifies them. The caller must hold
+// i.lk.
+func (i *IniSafe) keepReadOnly(cur, data Ini) Ini {
+	if len(i.readOnly) == 0 {
+		return data
+	}
+	res := make(Ini, len(data))
+	for n, s := range data {
+		res[n] = s
+	}
+	for section := range i.readOnly {
+		if s, ok := cur[section]; ok {
+			res[section] = s
+		} else {
+			delete(res, section)
+		}
+	}
+	return res
+}
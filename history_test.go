@@ -0,0 +1,80 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafeHistory(t *testing.T) {
+	i := ini.NewSafe()
+	i.EnableHistory(2)
+
+	i.Set("s", "k", "1")
+	i.Set("s", "k", "2")
+	i.Set("s", "k", "3")
+
+	check := func(want string) {
+		t.Helper()
+		if v, _ := i.Get("s", "k"); v != want {
+			t.Errorf("unexpected value %#v, expected %#v", v, want)
+		}
+	}
+
+	if !i.Undo() {
+		t.Fatalf("nothing to undo")
+	}
+	check("2")
+	if !i.Undo() {
+		t.Fatalf("nothing to undo")
+	}
+	check("1")
+	if i.Undo() {
+		t.Errorf("history was not bounded")
+	}
+
+	if !i.Redo() {
+		t.Fatalf("nothing to redo")
+	}
+	check("2")
+
+	i.Set("s", "k", "4")
+	if i.Redo() {
+		t.Errorf("redo history was not cleared by a new change")
+	}
+	check("4")
+}
+
+func TestIniSafeHistoryReadOnly(t *testing.T) {
+	i := ini.NewSafe()
+	i.EnableHistory(10)
+
+	i.Update(func(data ini.Ini) error {
+		data.Set("locked", "a", "1")
+		data.Set("open", "a", "1")
+		return nil
+	})
+	i.SetReadOnly("locked", true)
+
+	if !i.Undo() {
+		t.Fatalf("nothing to undo")
+	}
+	if v, ok := i.Get("locked", "a"); !ok || v != "1" {
+		t.Errorf("read-only section was reverted, got %q %v", v, ok)
+	}
+	if _, ok := i.Get("open", "a"); ok {
+		t.Errorf("section open was not reverted")
+	}
+
+	i.SetReadOnly("locked", false)
+	i.Unset("locked", "a")
+	i.Undo()
+	i.SetReadOnly("locked", true)
+	i.Undo() // back to the state before Update
+	if _, ok := i.Get("locked", "a"); !ok {
+		t.Errorf("read-only section was reverted")
+	}
+	if !i.Redo() {
+		t.Errorf("nothing to redo")
+	}
+}
@@ -0,0 +1,26 @@
+package ini
+
+import "sync/atomic"
+
+// Hooks are functions called on specific events, for example to maintain
+// metrics counters. Any of the functions can be nil. Hooks are global and
+// apply to all Ini instances.
+type Hooks struct {
+	// OnLoad is called after ini data has been loaded with the number of
+	// values read and the error returned, if any.
+	OnLoad func(keys int, err error)
+
+	// OnSet is called after a value has been changed with Set.
+	OnSet func(section, key string)
+
+	// OnGetMiss is called when Get is called for a value that doesn't exist.
+	OnGetMiss func(section, key string)
+}
+
+var hooks atomic.Pointer[Hooks]
+
+// SetHooks installs the given hooks, replacing any previously installed ones.
+// Passing nil removes all hooks.
+func SetHooks(h *Hooks) {
+	hooks.Store(h)
+}
@@ -0,0 +1,31 @@
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestHooks(t *testing.T) {
+	var loads, loadedKeys, sets, misses int
+	ini.SetHooks(&ini.Hooks{
+		OnLoad:    func(keys int, err error) { loads++; loadedKeys += keys },
+		OnSet:     func(section, key string) { sets++ },
+		OnGetMiss: func(section, key string) { misses++ },
+	})
+	defer ini.SetHooks(nil)
+
+	i := ini.New()
+	if err := i.Load(strings.NewReader("a=1\n[s]\nb=2\n")); err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	i.Set("s", "c", "3")
+	i.Get("s", "b")
+	i.Get("s", "missing")
+	i.Get("missing", "a")
+
+	if loads != 1 || loadedKeys != 2 || sets != 1 || misses != 2 {
+		t.Errorf("unexpected counters loads=%d keys=%d sets=%d misses=%d", loads, loadedKeys, sets, misses)
+	}
+}
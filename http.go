@@ -0,0 +1,18 @@
+package ini
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler returns a http.Handler serving the ini returned by get as JSON,
+// with secrets redacted. It can be mounted on a debug endpoint so operators
+// can inspect the effective configuration of a running service.
+func Handler(get func() Ini) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(get().Redacted())
+	})
+}
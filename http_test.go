@@ -0,0 +1,29 @@
+package ini_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestHandler(t *testing.T) {
+	i := ini.New()
+	i.Set("db", "host", "localhost")
+	i.Set("db", "password", "hunter2")
+
+	rec := httptest.NewRecorder()
+	ini.Handler(func() ini.Ini { return i }).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/config", nil))
+
+	var res map[string]map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	if v := res["db"]["host"]; v != "localhost" {
+		t.Errorf("unexpected value for db/host: %#v", v)
+	}
+	if v := res["db"]["password"]; v != ini.RedactedValue {
+		t.Errorf("password was not redacted: %#v", v)
+	}
+}
@@ -0,0 +1,85 @@
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth limits nested includes, which also stops include cycles
+const maxIncludeDepth = 10
+
+// isInclude returns true if key is an include directive
+func isInclude(key string) bool {
+	return key == "include" || key == "include-if-exists"
+}
+
+// include loads the file referenced by an include directive
+func (i Ini) include(key, value string, opts *LoadOptions) (int, error) {
+	if opts.depth >= maxIncludeDepth {
+		return 0, fmt.Errorf("too many nested includes")
+	}
+	path, err := expandIncludePath(value)
+	if err != nil {
+		return 0, err
+	}
+	if !filepath.IsAbs(path) && opts.dir != "" {
+		path = filepath.Join(opts.dir, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if key == "include-if-exists" && errors.Is(err, fs.ErrNotExist) {
+			if log := opts.logger(); log != nil {
+				log.Debug("ini: skipped missing include", "path", path)
+			}
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	if log := opts.logger(); log != nil {
+		log.Debug("ini: opened file", "path", path)
+	}
+
+	sub := *opts
+	sub.dir = filepath.Dir(path)
+	sub.depth++
+	sub.Comments = nil // comments of included files cannot be written back
+	return i.load(f, &sub)
+}
+
+// expandIncludePath replaces {hostname} and {env:NAME} in path
+func expandIncludePath(path string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			b.WriteString(path)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in include path %q", path)
+		}
+		b.WriteString(path[:start])
+
+		switch name := path[start+1 : start+end]; {
+		case name == "hostname":
+			h, err := os.Hostname()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(h)
+		case strings.HasPrefix(name, "env:"):
+			b.WriteString(os.Getenv(name[4:]))
+		default:
+			return "", fmt.Errorf("unknown placeholder {%s} in include path", name)
+		}
+		path = path[start+end+1:]
+	}
+}
@@ -0,0 +1,96 @@
+package ini_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestLoadIncludes(t *testing.T) {
+	dir := t.TempDir()
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no host name: %s", err)
+	}
+	t.Setenv("INI_TEST_ENV", "prod")
+
+	files := map[string]string{
+		"main.ini": "a=1\ninclude = base.ini\ninclude-if-exists = hosts/{hostname}.ini\n" +
+			"include-if-exists = missing.ini\n[s]\ninclude-if-exists = env/{env:INI_TEST_ENV}.ini\nb=2\n",
+		"base.ini":               "a=0\nbase=1\n",
+		"hosts/" + host + ".ini": "[s]\nhost=1\n",
+		"env/prod.ini":           "[s]\nenv=prod\nb=1\n",
+		"loop.ini":               "include = loop.ini\n",
+		"missing-include.ini":    "include = missing.ini\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("failed to write %s: %s", name, err)
+		}
+	}
+
+	opts := &ini.LoadOptions{Includes: true}
+	i := ini.New()
+	if err := i.LoadFile(filepath.Join(dir, "main.ini"), opts); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if d := i.Diff(ini.MustParse("a=0\nbase=1\n[s]\nhost=1\nenv=prod\nb=2\n")); len(d) != 0 {
+		t.Errorf("unexpected values: %v", d)
+	}
+
+	for _, name := range []string{"loop.ini", "missing-include.ini"} {
+		if err := ini.New().LoadFile(filepath.Join(dir, name), opts); err == nil {
+			t.Errorf("expected an error loading %s", name)
+		}
+	}
+
+	// without the option, directives are regular values
+	i = ini.New()
+	i.LoadFile(filepath.Join(dir, "loop.ini"), nil)
+	if v, _ := i.Get("root", "include"); v != "loop.ini" {
+		t.Errorf("unexpected value %q", v)
+	}
+}
+
+func TestLoadIncludesLimits(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"big.ini":      "[a]\nk1=1\nk2=2\nk3=3\n",
+		"repeat.ini":   strings.Repeat("include = big.ini\n", 10),
+		"section.ini":  "[b]\nk=1\n[c]\nk=1\n",
+		"sections.ini": "[x]\nk=1\ninclude = section.ini\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("failed to write %s: %s", name, err)
+		}
+	}
+
+	tests := []struct {
+		file string
+		opts ini.LoadOptions
+	}{
+		{"repeat.ini", ini.LoadOptions{MaxKeys: 10}},
+		{"repeat.ini", ini.LoadOptions{MaxInputSize: 200}},
+		{"sections.ini", ini.LoadOptions{MaxSections: 2}},
+	}
+	for _, test := range tests {
+		test.opts.Includes = true
+		err := ini.New().LoadFile(filepath.Join(dir, test.file), &test.opts)
+		var lerr *ini.LimitError
+		if !errors.As(err, &lerr) {
+			t.Errorf("expected a limit error loading %s with %+v, got %v", test.file, test.opts, err)
+		}
+	}
+
+	opts := &ini.LoadOptions{Includes: true, MaxKeys: 7}
+	if err := ini.New().LoadFile(filepath.Join(dir, "sections.ini"), opts); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
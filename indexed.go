@@ -0,0 +1,81 @@
+package ini
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indexOf returns the index of k if it is key followed by a bracketed index,
+// such as "key[2]"
+func indexOf(k, key string) (int, bool) {
+	rest, ok := strings.CutPrefix(k, key+"[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest[:len(rest)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// GetIndexed returns the values of keys encoding an array with an index
+// suffix, such as "key[0]", "key[1]", ordered by index. Gaps in the indices
+// are skipped. It returns nil if there is no such key.
+func (i Ini) GetIndexed(section, key string) []string {
+	key = strings.ToLower(key)
+	s := i[strings.ToLower(section)]
+
+	type entry struct {
+		n int
+		v string
+	}
+	var entries []entry
+	for k, v := range s {
+		if n, ok := indexOf(k, key); ok {
+			entries = append(entries, entry{n, v})
+		}
+	}
+	if entries == nil {
+		return nil
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].n < entries[b].n })
+
+	res := make([]string, len(entries))
+	for n, e := range entries {
+		res[n] = e.v
+	}
+	return res
+}
+
+// SetIndexed replaces the values of an array encoded with index suffixes,
+// storing values as "key[0]", "key[1]" and so on. Existing indexed keys are
+// removed first.
+func (i Ini) SetIndexed(section, key string, values []string) {
+	key = strings.ToLower(key)
+	for k := range i[strings.ToLower(section)] {
+		if _, ok := indexOf(k, key); ok {
+			i.Unset(section, k)
+		}
+	}
+	for n, v := range values {
+		i.Set(section, key+"["+strconv.Itoa(n)+"]", v)
+	}
+}
+
+// GetIndexed returns the values of keys encoding an array with an index
+// suffix, see Ini.GetIndexed.
+func (i *IniSafe) GetIndexed(section, key string) []string {
+	return i.snapshot().GetIndexed(section, key)
+}
+
+// SetIndexed replaces the values of an array encoded with index suffixes in
+// a single step, see Ini.SetIndexed. It returns ErrReadOnly if the section
+// is read-only.
+func (i *IniSafe) SetIndexed(section, key string, values []string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.SetIndexed(section, key, values)
+		return true
+	})
+}
@@ -0,0 +1,30 @@
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIndexed(t *testing.T) {
+	i := ini.MustParse("[s]\nhost[10]=c\nhost[0]=a\nhost[2]=b\nhost=x\nhosts[1]=y\nhost[x]=z\n")
+
+	if v := i.GetIndexed("S", "Host"); strings.Join(v, ",") != "a,b,c" {
+		t.Errorf("unexpected values %v", v)
+	}
+	if v := i.GetIndexed("s", "missing"); v != nil {
+		t.Errorf("unexpected values %v", v)
+	}
+
+	i.SetIndexed("s", "host", []string{"d", "e"})
+	if v := i.GetIndexed("s", "host"); strings.Join(v, ",") != "d,e" {
+		t.Errorf("unexpected values %v", v)
+	}
+	if _, ok := i.Get("s", "host[10]"); ok {
+		t.Errorf("previous values not removed")
+	}
+	if v, _ := i.Get("s", "host"); v != "x" {
+		t.Errorf("unrelated key modified")
+	}
+}
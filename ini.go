@@ -3,9 +3,9 @@ package ini
 // https://en.wikipedia.org/wiki/INI_file
 
 import (
-	"bufio"
-	"errors"
+	"fmt"
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -16,102 +16,289 @@ func New() Ini {
 	return make(Ini)
 }
 
+// Parse parses the ini data in src and returns a new Ini.
+func Parse(src string) (Ini, error) {
+	i := New()
+	if err := i.Load(strings.NewReader(src)); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// MustParse is like Parse but panics if src cannot be parsed. It is meant
+// for inline configurations known to be valid, such as defaults or tests.
+func MustParse(src string) Ini {
+	i, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
 // Load will parse source and merge loaded values
 func (i Ini) Load(source io.Reader) error {
-	r := bufio.NewScanner(source)
+	return i.LoadWithOptions(source, nil)
+}
+
+// LoadWithOptions will parse source and merge loaded values using the given
+// options. opts can be nil.
+func (i Ini) LoadWithOptions(source io.Reader, opts *LoadOptions) error {
+	n, err := i.load(source, opts)
+	if h := hooks.Load(); h != nil && h.OnLoad != nil {
+		h.OnLoad(n, err)
+	}
+	return err
+}
+
+// load parses source and returns the number of values loaded
+func (i Ini) load(source io.Reader, opts *LoadOptions) (int, error) {
 	section := "root"
 	var sectionMap map[string]string
+	log := opts.logger()
+	var seen map[string]bool // keys set by this source, only tracked when logging
+	if log != nil {
+		seen = make(map[string]bool)
+	}
+	n := 0
+	skip := false // in a conditional section not matching the platform
+	var comments *commentCollector
+	if opts != nil && opts.Comments != nil {
+		comments = &commentCollector{c: opts.Comments}
+	}
 
-	for r.Scan() {
-		line := strings.TrimSpace(r.Text())
-		if len(line) == 0 {
-			continue
+	if opts != nil && opts.state == nil {
+		// top level load, limits are shared with included files
+		o := *opts
+		o.state = &loadState{left: opts.MaxInputSize}
+		opts = &o
+	}
+
+	var limit *limitReader
+	if opts != nil && opts.MaxInputSize > 0 {
+		limit = &limitReader{r: source, state: opts.state, max: opts.MaxInputSize}
+		source = limit
+	}
+
+	// getSection sets sectionMap, creating the section if needed
+	getSection := func() error {
+		var ok bool
+		sectionMap, ok = i[section]
+		if !ok {
+			if opts != nil {
+				if opts.MaxSections > 0 && opts.state.sections >= opts.MaxSections {
+					return &LimitError{Limit: "MaxSections", Max: int64(opts.MaxSections)}
+				}
+				opts.state.sections++
+			}
+			sectionMap = make(map[string]string)
+			i[section] = sectionMap
 		}
+		return nil
+	}
 
-		if line[0] == ';' {
-			// comment line
-			continue
+	if opts != nil && opts.Template {
+		var err error
+		source, err = opts.execTemplate(source)
+		if err != nil {
+			if limit != nil && limit.err != nil {
+				err = limit.err
+			}
+			return 0, err
 		}
+	}
 
-		if line[0] == '[' && line[len(line)-1] == ']' {
-			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+	p := &Parser{
+		OnSection: func(name string, line int) error {
+			section = strings.ToLower(name)
 			sectionMap = nil
-			continue
-		}
+			skip = false
+			if opts != nil && opts.Conditional {
+				var match bool
+				var err error
+				section, match, err = conditionalSection(section)
+				if err != nil {
+					return fmt.Errorf("failed to parse ini file on line %d: %w", line, err)
+				}
+				skip = !match
+			}
+			repeated := false
+			if opts != nil && !skip && opts.RepeatedSections != nil {
+				base := section
+				if len(base) > 2 && base[0] == '[' && base[len(base)-1] == ']' {
+					// [[name]] header
+					base = strings.TrimSpace(base[1 : len(base)-1])
+				}
+				if isRepeated(base, opts.RepeatedSections) {
+					section = i.nextInstance(base)
+					repeated = true
+				}
+			}
+			if log != nil {
+				log.Debug("ini: parsed section", "section", section, "line", line, "skipped", skip)
+			}
+			if skip {
+				comments.element(line)
+				return nil
+			}
+			comments.section(section, line)
+			if repeated || (opts != nil && opts.KeepEmptySections) {
+				return getSection()
+			}
+			return nil
+		},
+		OnKeyValue: func(_, key, value string, line int) error {
+			if skip {
+				comments.element(line)
+				return nil
+			}
+			k := strings.ToLower(key)
+			if opts != nil {
+				// include directives count as keys too, so they cannot be
+				// repeated without limit
+				if opts.MaxKeys > 0 && opts.state.keys >= opts.MaxKeys {
+					return &LimitError{Limit: "MaxKeys", Max: int64(opts.MaxKeys)}
+				}
+				if opts.MaxValueLength > 0 && len(value) > opts.MaxValueLength {
+					return &LimitError{Limit: "MaxValueLength", Max: int64(opts.MaxValueLength)}
+				}
+				opts.state.keys++
+			}
+			if opts != nil && opts.Includes && isInclude(k) {
+				c, err := i.include(k, value, opts)
+				if err != nil {
+					return fmt.Errorf("failed to parse ini file on line %d: %w", line, err)
+				}
+				n += c
+				sectionMap = nil
+				return nil
+			}
+			comments.key(section, k, line)
 
-		pos := strings.IndexByte(line, '=')
-		if pos < 0 {
-			return errors.New("failed to parse ini file: invalid line")
-		}
+			if sectionMap == nil {
+				if err := getSection(); err != nil {
+					return err
+				}
+			}
 
-		k := strings.ToLower(strings.TrimSpace(line[:pos]))
-		line = strings.TrimSpace(line[pos+1:])
+			if log != nil {
+				if old, ok := sectionMap[k]; ok {
+					if seen[section+"\x00"+k] {
+						log.Warn("ini: duplicate key", "section", section, "key", k, "line", line)
+					} else if old != value {
+						log.Debug("ini: overriding key", "section", section, "key", k, "line", line)
+					}
+				}
+				seen[section+"\x00"+k] = true
+			}
 
-		// TODO: handle quotes, handle escape characters
+			sectionMap[k] = value
+			n++
+			return nil
+		},
+	}
 
-		if sectionMap == nil {
-			var ok bool
-			sectionMap, ok = i[section]
-			if !ok {
-				sectionMap = make(map[string]string)
-				i[section] = sectionMap
+	if comments != nil {
+		p.OnComment = comments.comment
+	}
+	if opts != nil {
+		p.Unquote = opts.Unquote
+	}
+	if opts != nil && opts.Lenient {
+		p.OnInvalid = func(text string, line int) error {
+			if log != nil {
+				log.Warn("ini: ignoring invalid line", "section", section, "line", line)
 			}
+			if comments != nil {
+				comments.invalid(text, line)
+			}
+			return nil
 		}
-
-		sectionMap[k] = line
 	}
 
-	return r.Err()
+	err := p.Parse(source)
+	comments.end()
+	if limit != nil && limit.err != nil {
+		err = limit.err
+	}
+	if err != nil && log != nil {
+		log.Warn("ini: parse failed", "section", section, "error", err)
+	}
+	return n, err
 }
 
-// Write generates a ini file and writes it to the provided output
+// Write generates a ini file and writes it to the provided output. Sections
+// and keys are written in alphabetical order, root section first. Values are
+// written as is, so a plain Load reads them back unchanged; see
+// WriteOptions.Quote to quote them for LoadOptions.Unquote instead. Values
+// containing line breaks cannot be written this way and cause an error.
+// Output is buffered, so d receives a few large writes rather than one per
+// line.
 func (i Ini) Write(d io.Writer) error {
-	if s, ok := i["root"]; ok {
-		if err := i.writeSection(d, s); err != nil {
-			return err
-		}
+	return i.WriteWithOptions(d, nil)
+}
+
+// Sections returns the names of all sections, in no particular order.
+func (i Ini) Sections() []string {
+	res := make([]string, 0, len(i))
+	for n := range i {
+		res = append(res, n)
 	}
+	return res
+}
 
-	for n, s := range i {
-		if n == "root" {
-			continue
-		}
+// Keys returns the names of all keys in a section, in no particular order.
+func (i Ini) Keys(section string) []string {
+	s := i[strings.ToLower(section)]
+	res := make([]string, 0, len(s))
+	for k := range s {
+		res = append(res, k)
+	}
+	return res
+}
 
-		_, err := d.Write(append(append([]byte{'['}, []byte(n)...), ']', '\n'))
-		if err != nil {
-			return err
-		}
+// SectionsSorted returns the names of all sections in alphabetical order.
+// Use sort.Slice with NaturalLess for a numeric aware order.
+func (i Ini) SectionsSorted() []string {
+	return sortedKeys(i)
+}
 
-		if err := i.writeSection(d, s); err != nil {
-			return err
-		}
-	}
-	return nil
+// KeysSorted returns the names of all keys in a section in alphabetical
+// order.
+func (i Ini) KeysSorted(section string) []string {
+	return sortedKeys(i[strings.ToLower(section)])
 }
 
-func (i Ini) writeSection(d io.Writer, s map[string]string) error {
+// SectionMap returns a copy of the values of a section, or nil if the section
+// does not exist. Modifying the returned map does not affect the ini.
+func (i Ini) SectionMap(section string) map[string]string {
+	s, ok := i[strings.ToLower(section)]
+	if !ok {
+		return nil
+	}
+	res := make(map[string]string, len(s))
 	for k, v := range s {
-		_, err := d.Write(append(append(append([]byte(k), '='), []byte(v)...), '\n'))
-		if err != nil {
-			return err
-		}
+		res[k] = v
 	}
-	_, err := d.Write([]byte{'\n'})
-	return err
+	return res
 }
 
 // Get returns a value for a given key. Use section "root" for entries at the
 // beginning of the file.
 func (i Ini) Get(section, key string) (string, bool) {
-	s, ok := i[strings.ToLower(section)]
+	r, ok := i[strings.ToLower(section)][strings.ToLower(key)]
 	if !ok {
-		return "", false
+		getMiss(section, key)
 	}
-
-	r, ok := s[strings.ToLower(key)]
 	return r, ok
 }
 
+// getMiss calls the OnGetMiss hook, if any
+func getMiss(section, key string) {
+	if h := hooks.Load(); h != nil && h.OnGetMiss != nil {
+		h.OnGetMiss(section, key)
+	}
+}
+
 // Set changes a value in the ini file
 func (i Ini) Set(section, key, value string) {
 	s, ok := i[strings.ToLower(section)]
@@ -121,6 +308,90 @@ func (i Ini) Set(section, key, value string) {
 	}
 
 	s[strings.ToLower(key)] = value
+
+	if h := hooks.Load(); h != nil && h.OnSet != nil {
+		h.OnSet(section, key)
+	}
+}
+
+// SetE changes a value like Set, but first checks that the section and key
+// names can be written and loaded back. Section names cannot be empty or
+// contain '[', ']' or line breaks, and key names cannot be empty, contain
+// '=' or line breaks, or start with ';' or '['. Names with surrounding spaces
+// are rejected too. Any value is accepted, though values with surrounding
+// spaces or line breaks need a WriteOptions.Quote style to be written back.
+func (i Ini) SetE(section, key, value string) error {
+	return i.SetEWithOptions(section, key, value, nil)
+}
+
+// SetEWithOptions changes a value like SetE, checking names with the given
+// options. opts can be nil.
+func (i Ini) SetEWithOptions(section, key, value string, opts *NameOptions) error {
+	if err := CheckNameWithOptions(section, key, opts); err != nil {
+		return err
+	}
+	i.Set(section, key, value)
+	return nil
+}
+
+// NameOptions holds settings used by SetEWithOptions and
+// CheckNameWithOptions to validate section and key names.
+type NameOptions struct {
+	// SectionChars and KeyChars are the characters rejected in section and
+	// key names. Empty strings select the defaults, "[]" and "=". Line
+	// breaks are always rejected as they cannot be written.
+	SectionChars string
+	KeyChars     string
+
+	// Validate, if set, is called for names that passed the other checks and
+	// can reject them by returning an error.
+	Validate func(section, key string) error
+}
+
+// CheckName returns an error if the section or key name cannot be written
+// and loaded back, see SetE. It can be used to build stricter checks.
+func CheckName(section, key string) error {
+	return CheckNameWithOptions(section, key, nil)
+}
+
+// CheckNameWithOptions checks names like CheckName, using the given options.
+// opts can be nil.
+func CheckNameWithOptions(section, key string, opts *NameOptions) error {
+	if opts == nil {
+		opts = &NameOptions{}
+	}
+	sectionChars, keyChars := opts.SectionChars, opts.KeyChars
+	if sectionChars == "" {
+		sectionChars = invalidSectionChars
+	}
+	if keyChars == "" {
+		keyChars = invalidKeyChars
+	}
+	if err := checkSectionChars(section, sectionChars); err != nil {
+		return err
+	}
+	if err := checkKeyChars(section, key, keyChars); err != nil {
+		return err
+	}
+	if opts.Validate != nil {
+		return opts.Validate(section, key)
+	}
+	return nil
+}
+
+// SetMany changes multiple values of a section.
+func (i Ini) SetMany(section string, kv map[string]string) {
+	for k, v := range kv {
+		i.Set(section, k, v)
+	}
+}
+
+// SetSection replaces all the values of a section with kv. The section is
+// removed if kv is empty. Use SetMany to merge values into a section
+// instead.
+func (i Ini) SetSection(section string, kv map[string]string) {
+	i.DeleteSection(section)
+	i.SetMany(section, kv)
 }
 
 // Unset removes a value from the ini file
@@ -136,3 +407,46 @@ func (i Ini) Unset(section, key string) {
 		delete(i, strings.ToLower(section))
 	}
 }
+
+// UnsetKeep removes a value like Unset, but keeps the section even if it
+// becomes empty.
+func (i Ini) UnsetKeep(section, key string) {
+	delete(i[strings.ToLower(section)], strings.ToLower(key))
+}
+
+// AddSection creates a section without values if it does not exist yet. Empty
+// sections are written as a single header line.
+func (i Ini) AddSection(section string) {
+	if _, ok := i[strings.ToLower(section)]; !ok {
+		i[strings.ToLower(section)] = make(map[string]string)
+	}
+}
+
+// DeleteSection removes a section and all its values
+func (i Ini) DeleteSection(section string) {
+	delete(i, strings.ToLower(section))
+}
+
+// sortedSections returns the names of sections in the order they are
+// written: root first, then alphabetically
+func (i Ini) sortedSections() []string {
+	res := sortedKeys(i)
+	for n, name := range res {
+		if name == "root" {
+			copy(res[1:n+1], res[:n])
+			res[0] = "root"
+			break
+		}
+	}
+	return res
+}
+
+// sortedKeys returns the keys of m in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -2,6 +2,9 @@ package ini_test
 
 import (
 	"bytes"
+	"errors"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/KarpelesLab/ini"
@@ -32,3 +35,212 @@ var2=value4`
 		t.Errorf("failed to get value section/var2, read %#v %#v", v, ok)
 	}
 }
+
+func TestIniQuotes(t *testing.T) {
+	f := `var1="  spaced  "
+var2='c:\dir'
+var3="line1\nline2 \"quoted\""
+var4="unterminated`
+
+	i := ini.New()
+	if err := i.LoadWithOptions(bytes.NewReader([]byte(f)), &ini.LoadOptions{Unquote: true}); err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+
+	expect := map[string]string{
+		"var1": "  spaced  ",
+		"var2": `c:\dir`,
+		"var3": "line1\nline2 \"quoted\"",
+		"var4": `"unterminated`,
+	}
+	for k, want := range expect {
+		if v, ok := i.Get("root", k); !ok || v != want {
+			t.Errorf("failed to get value root/%s, read %#v %#v", k, v, ok)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Quote: ini.QuoteMinimal}); err != nil {
+		t.Fatalf("failed to write ini: %s", err)
+	}
+
+	res := ini.New()
+	if err := res.LoadWithOptions(buf, &ini.LoadOptions{Unquote: true}); err != nil {
+		t.Fatalf("failed to parse written ini: %s", err)
+	}
+	if d := i.Diff(res); len(d) != 0 {
+		t.Errorf("values changed after write: %v", d)
+	}
+}
+
+func TestIniQuotesLiteral(t *testing.T) {
+	f := `path="C:\temp\new"
+trailing="a\"
+multi="foo" bar "baz"
+unknown="a\qb"`
+
+	// without Unquote, values are kept as found
+	i := ini.MustParse(f)
+	if v, _ := i.Get("root", "path"); v != `"C:\temp\new"` {
+		t.Errorf("unexpected path %#v", v)
+	}
+
+	i = ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(f), &ini.LoadOptions{Unquote: true}); err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	expect := map[string]string{
+		"path":     "C:\temp\new",
+		"trailing": `"a\"`,
+		"multi":    `"foo" bar "baz"`,
+		"unknown":  `a\qb`,
+	}
+	for k, want := range expect {
+		if v, _ := i.Get("root", k); v != want {
+			t.Errorf("unexpected value for %s: %#v, expected %#v", k, v, want)
+		}
+	}
+}
+
+func TestIniRoundTrip(t *testing.T) {
+	// a plain Load followed by Write gives the file back
+	f := "name='x'\npath=\"C:\\temp\"\nwin=C:\\xyz\n\n[s]\nq=\"a\" b \"c\"\n\n"
+
+	buf := &bytes.Buffer{}
+	if err := ini.MustParse(f).Write(buf); err != nil {
+		t.Fatalf("failed to write ini: %s", err)
+	}
+	if buf.String() != f {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	// and so does a second cycle
+	out := buf.String()
+	buf.Reset()
+	if err := ini.MustParse(out).Write(buf); err != nil || buf.String() != f {
+		t.Errorf("unexpected output %q after a second cycle", buf.String())
+	}
+}
+
+// mustParseUnquote parses src with LoadOptions.Unquote, panicking on errors
+func mustParseUnquote(src string) ini.Ini {
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Unquote: true}); err != nil {
+		panic(err)
+	}
+	return i
+}
+
+func TestParse(t *testing.T) {
+	i := ini.MustParse("var1=value1\n[section]\nvar2=value2")
+	if v, ok := i.Get("section", "var2"); !ok || v != "value2" {
+		t.Errorf("failed to get value section/var2, read %#v %#v", v, ok)
+	}
+
+	if _, err := ini.Parse("invalid"); err == nil {
+		t.Errorf("expected error when parsing invalid data")
+	}
+}
+
+func TestSectionsKeys(t *testing.T) {
+	i := ini.MustParse("var1=value1\n[section]\nvar2=value2\nvar3=value3")
+
+	s := i.Sections()
+	sort.Strings(s)
+	if strings.Join(s, ",") != "root,section" {
+		t.Errorf("unexpected sections %v", s)
+	}
+
+	k := i.Keys("Section")
+	sort.Strings(k)
+	if strings.Join(k, ",") != "var2,var3" {
+		t.Errorf("unexpected keys %v", k)
+	}
+}
+
+func TestSetSection(t *testing.T) {
+	i := ini.MustParse("[s]\na=1\nb=2\n")
+
+	i.SetMany("s", map[string]string{"b": "3", "c": "4"})
+	if d := i.Diff(ini.MustParse("[s]\na=1\nb=3\nc=4\n")); len(d) != 0 {
+		t.Errorf("unexpected changes after SetMany: %v", d)
+	}
+
+	i.SetSection("S", map[string]string{"D": "5"})
+	if d := i.Diff(ini.MustParse("[s]\nd=5\n")); len(d) != 0 {
+		t.Errorf("unexpected changes after SetSection: %v", d)
+	}
+
+	i.SetSection("s", nil)
+	if _, ok := i["s"]; ok {
+		t.Errorf("section not removed")
+	}
+}
+
+func TestSectionMap(t *testing.T) {
+	i := ini.MustParse("[s]\na=1\nb=2\n")
+
+	m := i.SectionMap("S")
+	if len(m) != 2 || m["a"] != "1" || m["b"] != "2" {
+		t.Errorf("unexpected map %v", m)
+	}
+	m["a"] = "changed"
+	if v, _ := i.Get("s", "a"); v != "1" {
+		t.Errorf("modifying the map changed the ini")
+	}
+
+	if m := i.SectionMap("missing"); m != nil {
+		t.Errorf("unexpected map %v for a missing section", m)
+	}
+}
+
+func TestSetE(t *testing.T) {
+	i := ini.New()
+
+	if err := i.SetE("section", "key", "multi\nline value"); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	for _, name := range [][2]string{
+		{"sec]tion", "key"},
+		{"section\n", "key"},
+		{"", "key"},
+		{"section", "k=ey"},
+		{"section", "key\nother"},
+		{"section", "[key"},
+		{"section", " key"},
+	} {
+		if err := i.SetE(name[0], name[1], "value"); err == nil {
+			t.Errorf("expected an error for %q", name)
+		}
+	}
+
+	if len(i.Sections()) != 1 {
+		t.Errorf("invalid names were stored")
+	}
+
+	opts := &ini.NameOptions{
+		KeyChars: "=.",
+		Validate: func(section, key string) error {
+			if strings.HasPrefix(key, "x") {
+				return errors.New("reserved key")
+			}
+			return nil
+		},
+	}
+	if err := i.SetEWithOptions("section", "k.ey", "value", opts); err == nil {
+		t.Errorf("expected an error for a key with a configured character")
+	}
+	if err := i.SetEWithOptions("section", "xkey", "value", opts); err == nil {
+		t.Errorf("expected an error from Validate")
+	}
+	if err := i.SetEWithOptions("section", "key\n", "value", opts); err == nil {
+		t.Errorf("expected an error for a line break")
+	}
+	if err := i.SetEWithOptions("sec]tion", "key", "value", opts); err == nil {
+		t.Errorf("expected an error for a default section character")
+	}
+	if err := i.SetEWithOptions("section", "other", "value", opts); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
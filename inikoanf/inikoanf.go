@@ -0,0 +1,128 @@
+// Package inikoanf implements the koanf Provider and Parser interfaces on top
+// of github.com/KarpelesLab/ini.
+//
+//	k := koanf.New(".")
+//	k.Load(file.Provider("config.ini"), inikoanf.Parser())
+//
+// Sections are exposed as maps of their keys, and values of the root section
+// are stored at the top level. Dotted section names such as [server.http] are
+// exposed as nested maps, matching what Marshal generates.
+package inikoanf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	_ koanf.Parser   = (*IniParser)(nil)
+	_ koanf.Provider = (*IniProvider)(nil)
+)
+
+// IniParser implements koanf.Parser.
+type IniParser struct{}
+
+// IniProvider implements koanf.Provider for an in-memory Ini.
+type IniProvider struct {
+	i ini.Ini
+}
+
+// Parser returns an ini parser for koanf.
+func Parser() *IniParser {
+	return &IniParser{}
+}
+
+// Provider returns a koanf provider serving the values of i.
+func Provider(i ini.Ini) *IniProvider {
+	return &IniProvider{i: i}
+}
+
+// Unmarshal parses ini data into a nested map.
+func (p *IniParser) Unmarshal(b []byte) (map[string]any, error) {
+	i := ini.New()
+	if err := i.Load(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return toMap(i)
+}
+
+// Marshal generates ini data from a nested map. Maps nested more than one
+// level deep are stored in dotted section names.
+func (p *IniParser) Marshal(m map[string]any) ([]byte, error) {
+	i := ini.New()
+	if err := fromMap(i, "root", "", m); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := i.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadBytes returns the serialized ini.
+func (p *IniProvider) ReadBytes() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := p.i.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Read returns the values of the ini as a nested map.
+func (p *IniProvider) Read() (map[string]any, error) {
+	return toMap(p.i)
+}
+
+func toMap(i ini.Ini) (map[string]any, error) {
+	res := make(map[string]any)
+
+	for n, s := range i {
+		m := res
+		if n != "root" {
+			for _, part := range strings.Split(n, ".") {
+				switch sub := m[part].(type) {
+				case map[string]any:
+					m = sub
+				case nil:
+					m[part] = make(map[string]any)
+					m = m[part].(map[string]any)
+				default:
+					return nil, fmt.Errorf("section %s conflicts with a key", n)
+				}
+			}
+		}
+
+		for k, v := range s {
+			if _, ok := m[k].(map[string]any); ok {
+				return nil, fmt.Errorf("key %s.%s conflicts with a section", n, k)
+			}
+			m[k] = v
+		}
+	}
+
+	return res, nil
+}
+
+func fromMap(i ini.Ini, section, prefix string, m map[string]any) error {
+	for k, v := range m {
+		switch v := v.(type) {
+		case map[string]any:
+			if err := fromMap(i, prefix+k, prefix+k+".", v); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("unsupported list value for %s.%s", section, k)
+		case nil:
+			i.Set(section, k, "")
+		default:
+			i.Set(section, k, fmt.Sprint(v))
+		}
+	}
+	return nil
+}
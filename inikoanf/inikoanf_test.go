@@ -0,0 +1,44 @@
+package inikoanf_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini/inikoanf"
+)
+
+func TestParser(t *testing.T) {
+	p := inikoanf.Parser()
+
+	m, err := p.Unmarshal([]byte("var1=value1\n[section]\nvar2=value2\n"))
+	if err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	if v := m["var1"]; v != "value1" {
+		t.Errorf("invalid value for var1: %#v", v)
+	}
+	if s, ok := m["section"].(map[string]any); !ok || s["var2"] != "value2" {
+		t.Errorf("invalid value for section: %#v", m["section"])
+	}
+
+	buf, err := p.Marshal(map[string]any{"server": map[string]any{"http": map[string]any{"port": 8080}}})
+	if err != nil {
+		t.Fatalf("failed to generate ini: %s", err)
+	}
+	if string(buf) != "[server.http]\nport=8080\n\n" {
+		t.Errorf("unexpected output %q", buf)
+	}
+
+	// dotted sections come back nested, as Marshal expects them
+	m, err = p.Unmarshal(buf)
+	if err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	srv, _ := m["server"].(map[string]any)
+	if h, ok := srv["http"].(map[string]any); !ok || h["port"] != "8080" {
+		t.Errorf("invalid value for server: %#v", m["server"])
+	}
+
+	if _, err := p.Unmarshal([]byte("[server]\nhttp=on\n[server.http]\nport=80\n")); err == nil {
+		t.Errorf("expected an error for a key conflicting with a section")
+	}
+}
@@ -0,0 +1,50 @@
+// Package iniotel provides context-aware variants of the ini read and write
+// methods that record OpenTelemetry spans, so the time spent loading and
+// saving configuration shows up in traces.
+package iniotel
+
+import (
+	"context"
+	"io"
+
+	"github.com/KarpelesLab/ini"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/KarpelesLab/ini"
+
+// ReadFrom calls i.ReadFrom(r) inside a span named "ini.ReadFrom". The span
+// records the number of bytes read and the number of sections after loading.
+func ReadFrom(ctx context.Context, i ini.Ini, r io.Reader) (int64, error) {
+	_, span := otel.Tracer(tracerName).Start(ctx, "ini.ReadFrom")
+	defer span.End()
+
+	n, err := i.ReadFrom(r)
+	finish(span, i, n, err)
+	return n, err
+}
+
+// WriteTo calls i.WriteTo(w) inside a span named "ini.WriteTo". The span
+// records the number of bytes written and the number of sections.
+func WriteTo(ctx context.Context, i ini.Ini, w io.Writer) (int64, error) {
+	_, span := otel.Tracer(tracerName).Start(ctx, "ini.WriteTo")
+	defer span.End()
+
+	n, err := i.WriteTo(w)
+	finish(span, i, n, err)
+	return n, err
+}
+
+func finish(span trace.Span, i ini.Ini, n int64, err error) {
+	span.SetAttributes(
+		attribute.Int64("ini.bytes", n),
+		attribute.Int("ini.sections", len(i)),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package iniotel_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+	"github.com/KarpelesLab/ini/iniotel"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestReadFrom(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	src := "a=1\n[s]\nb=2\n"
+	i := ini.New()
+	n, err := iniotel.ReadFrom(context.Background(), i, strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	if n != int64(len(src)) {
+		t.Errorf("unexpected byte count %d", n)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Name() != "ini.ReadFrom" {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+	for _, a := range spans[0].Attributes() {
+		if a.Key == "ini.sections" && a.Value.AsInt64() != 2 {
+			t.Errorf("unexpected section count %d", a.Value.AsInt64())
+		}
+	}
+}
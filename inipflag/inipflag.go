@@ -0,0 +1,83 @@
+// Package inipflag binds github.com/spf13/pflag flag sets, as used by cobra,
+// to values stored in a github.com/KarpelesLab/ini file.
+package inipflag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KarpelesLab/ini"
+	"github.com/spf13/pflag"
+)
+
+// NameMapper returns the section and key storing the value of a given flag.
+// An empty section skips the flag.
+type NameMapper func(name string) (section, key string)
+
+// DotMapper maps flags named "section.key" to the matching section and key,
+// and flags without a dot to the root section.
+func DotMapper(name string) (string, string) {
+	pos := strings.IndexByte(name, '.')
+	if pos < 0 {
+		return "root", name
+	}
+	return name[:pos], name[pos+1:]
+}
+
+// SectionMapper returns a NameMapper storing all flags in the given section
+// using their name as key.
+func SectionMapper(section string) NameMapper {
+	return func(name string) (string, string) {
+		return section, name
+	}
+}
+
+// Bind sets the default value of each flag in fs from the ini. It should be
+// called before fs.Parse so that command line arguments take precedence. If
+// mapper is nil, DotMapper is used.
+func Bind(i ini.Ini, fs *pflag.FlagSet, mapper NameMapper) error {
+	if mapper == nil {
+		mapper = DotMapper
+	}
+
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		section, key := mapper(f.Name)
+		if section == "" {
+			return
+		}
+		v, ok := i.Get(section, key)
+		if !ok {
+			return
+		}
+		if e := f.Value.Set(v); e != nil {
+			err = fmt.Errorf("invalid value for %s.%s: %w", section, key, e)
+			return
+		}
+		f.DefValue = v
+	})
+
+	return err
+}
+
+// Save stores the value of each flag changed on the command line into the
+// ini. If mapper is nil, DotMapper is used.
+func Save(i ini.Ini, fs *pflag.FlagSet, mapper NameMapper) {
+	if mapper == nil {
+		mapper = DotMapper
+	}
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		section, key := mapper(f.Name)
+		if section == "" {
+			return
+		}
+		i.Set(section, key, f.Value.String())
+	})
+}
@@ -0,0 +1,35 @@
+package inipflag_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+	"github.com/KarpelesLab/ini/inipflag"
+	"github.com/spf13/pflag"
+)
+
+func TestBind(t *testing.T) {
+	i := ini.New()
+	i.Set("db", "host", "localhost")
+	i.Set("root", "verbose", "true")
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	host := fs.String("db.host", "", "database host")
+	port := fs.Int("db.port", 5432, "database port")
+	verbose := fs.Bool("verbose", false, "verbose output")
+
+	if err := inipflag.Bind(i, fs, nil); err != nil {
+		t.Fatalf("failed to bind flags: %s", err)
+	}
+	if err := fs.Parse([]string{"--db.port", "6543"}); err != nil {
+		t.Fatalf("failed to parse flags: %s", err)
+	}
+	if *host != "localhost" || *port != 6543 || !*verbose {
+		t.Errorf("unexpected flag values %s %d %v", *host, *port, *verbose)
+	}
+
+	inipflag.Save(i, fs, nil)
+	if v, _ := i.Get("db", "port"); v != "6543" {
+		t.Errorf("flag was not saved, got %#v", v)
+	}
+}
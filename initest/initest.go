@@ -0,0 +1,86 @@
+// Package initest provides helpers for tests handling ini data.
+package initest
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+// UpdateEnv is the name of the environment variable that, when set to a
+// non-empty value, causes AssertGolden to write golden files instead of
+// comparing them.
+const UpdateEnv = "INITEST_UPDATE"
+
+// MustParse parses src and fails the test immediately if it isn't valid.
+func MustParse(t testing.TB, src string) ini.Ini {
+	t.Helper()
+
+	i, err := ini.Parse(src)
+	if err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	return i
+}
+
+// AssertEqual reports an error listing the differences between want and got
+// if they hold different values.
+func AssertEqual(t testing.TB, want, got ini.Ini) bool {
+	t.Helper()
+
+	changes := want.Diff(got)
+	if len(changes) == 0 {
+		return true
+	}
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, "\t"+c.String())
+	}
+	t.Errorf("ini values differ (- want, + got):\n%s", strings.Join(lines, "\n"))
+	return false
+}
+
+// AssertGolden compares the serialized form of got with the contents of the
+// golden file at path, and reports an error if they differ. If the
+// environment variable named by UpdateEnv is set, the golden file is written
+// instead.
+func AssertGolden(t testing.TB, path string, got ini.Ini) bool {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := got.Write(buf); err != nil {
+		t.Fatalf("failed to write ini: %s", err)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %s", err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %s", err)
+	}
+	if bytes.Equal(want, buf.Bytes()) {
+		return true
+	}
+
+	// Write keeps values as is, so the golden file is read back with a
+	// plain Load, as got would be
+	wantIni := ini.New()
+	if err := wantIni.Load(bytes.NewReader(want)); err != nil {
+		t.Errorf("golden file %s differs and cannot be parsed: %s", path, err)
+		return false
+	}
+	if !AssertEqual(t, wantIni, got) {
+		return false
+	}
+	t.Errorf("golden file %s differs in formatting only, set %s=1 to update", path, UpdateEnv)
+	return false
+}
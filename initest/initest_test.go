@@ -0,0 +1,51 @@
+package initest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini/initest"
+)
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.ini")
+	if err := os.WriteFile(path, []byte("var1=value1\n\n[section]\nvar2=value2\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i := initest.MustParse(t, "[section]\nvar2=value2\n[root]\nvar1=value1\n")
+	initest.AssertGolden(t, path, i)
+	initest.AssertEqual(t, initest.MustParse(t, "var1=value1\n[section]\nvar2=value2"), i)
+}
+
+// recorder is a testing.TB collecting errors instead of failing
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGoldenQuoted(t *testing.T) {
+	golden := "path = \"C:\\temp\"\nname = 'x'\n"
+	path := filepath.Join(t.TempDir(), "golden.ini")
+	if err := os.WriteFile(path, []byte(golden), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// same values with different spacing: only the formatting differs
+	r := &recorder{TB: t}
+	if initest.AssertGolden(r, path, initest.MustParse(t, golden)) {
+		t.Errorf("expected the golden file to differ")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "formatting only") {
+		t.Errorf("unexpected errors %q", r.errors)
+	}
+}
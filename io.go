@@ -0,0 +1,65 @@
+package ini
+
+import (
+	"io"
+	"strings"
+)
+
+// ReadFrom implements io.ReaderFrom. It parses ini data from r, merges the
+// loaded values and returns the number of bytes read.
+func (i Ini) ReadFrom(r io.Reader) (int64, error) {
+	c := &countReader{r: r}
+	err := i.Load(c)
+	return c.n, err
+}
+
+// WriteTo implements io.WriterTo. It writes the ini to w and returns the
+// number of bytes written.
+func (i Ini) WriteTo(w io.Writer) (int64, error) {
+	c := &countWriter{w: w}
+	err := i.Write(c)
+	return c.n, err
+}
+
+// Reader returns a reader streaming the ini as generated by Write. Data is
+// generated as it is read, so the whole output is never held in memory. The
+// ini must not be modified until the reader returned EOF or was closed, and
+// the reader must be either read until EOF or closed.
+func (i Ini) Reader() io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(i.Write(w))
+	}()
+	return r
+}
+
+// String implements fmt.Stringer and returns the ini as generated by Write,
+// so it prints as a readable configuration with %s or %v. Use
+// i.Redacted().String() when the output may end up in logs.
+func (i Ini) String() string {
+	b := &strings.Builder{}
+	i.Write(b)
+	return b.String()
+}
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
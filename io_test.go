@@ -0,0 +1,112 @@
+package ini_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+// countingWriter counts calls to Write
+type countingWriter struct {
+	bytes.Buffer
+	calls int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.calls++
+	return w.Buffer.Write(p)
+}
+
+func TestWriteTo(t *testing.T) {
+	i := ini.New()
+	for _, s := range []string{"a", "b", "c"} {
+		for _, k := range []string{"x", "y", "z"} {
+			i.Set(s, k, "value")
+		}
+	}
+
+	w := &countingWriter{}
+	n, err := i.WriteTo(w)
+	if err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	if n != int64(w.Len()) {
+		t.Errorf("reported %d bytes written, got %d", n, w.Len())
+	}
+	if w.calls != 1 {
+		t.Errorf("output was not buffered, got %d writes", w.calls)
+	}
+
+	res := ini.New()
+	if _, err := res.ReadFrom(&w.Buffer); err != nil {
+		t.Fatalf("failed to read: %s", err)
+	}
+	if d := i.Diff(res); len(d) != 0 {
+		t.Errorf("values changed after write: %v", d)
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	i := ini.New()
+	i.Load(bytes.NewReader(benchData()))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		if err := i.Write(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadSmall(b *testing.B) {
+	data := []byte("var1=value1\n[section]\nvar2=value2\n")
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		if err := ini.New().Load(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	i := ini.MustParse("a=1\n[b]\nc=2\n")
+
+	r := i.Reader()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read: %s", err)
+	}
+	r.Close()
+
+	buf := &bytes.Buffer{}
+	i.Write(buf)
+	if string(out) != buf.String() {
+		t.Errorf("unexpected output %q, expected %q", out, buf.String())
+	}
+
+	// closing before EOF must not block the writer
+	r = i.Reader()
+	r.Close()
+}
+
+func TestString(t *testing.T) {
+	i := ini.MustParse("a=1\n[db]\npassword=hunter2\n")
+
+	if s := fmt.Sprintf("%v", i); s != "a=1\n\n[db]\npassword=hunter2\n\n" {
+		t.Errorf("unexpected string %q", s)
+	}
+	if s := i.Redacted().String(); strings.Contains(s, "hunter2") {
+		t.Errorf("secret not redacted in %q", s)
+	}
+
+	safe := ini.NewSafe()
+	safe.Load(strings.NewReader("a=1\n"))
+	if s := fmt.Sprint(safe); s != "a=1\n\n" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
@@ -0,0 +1,62 @@
+package ini
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ToJSON returns a json object where each section is an object of keys to
+// values. Entries of the root section are stored under "root".
+func (i Ini) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(map[string]map[string]string(i), "", "  ")
+}
+
+// FromJSON parses a json object made of two levels (section, then key) and
+// returns the matching Ini. Top-level scalars are stored in the root section.
+func FromJSON(data []byte) (Ini, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	res := New()
+	for n, v := range doc {
+		if s, ok := v.(map[string]any); ok {
+			for k, sv := range s {
+				str, err := jsonScalar(sv)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse json: %s.%s: %w", n, k, err)
+				}
+				res.Set(n, k, str)
+			}
+			continue
+		}
+
+		str, err := jsonScalar(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse json: %s: %w", n, err)
+		}
+		res.Set("root", n, str)
+	}
+
+	return res, nil
+}
+
+func jsonScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
@@ -0,0 +1,194 @@
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// LoadOptions holds settings used when loading ini data.
+type LoadOptions struct {
+	// Logger, if set, receives debug events about the parsing process, such
+	// as opened files, parsed sections and overridden keys, and warnings
+	// about suspicious content.
+	Logger *slog.Logger
+
+	// Comments, if set, receives the comments found while loading. See
+	// WriteOptions to write them back.
+	Comments *Comments
+
+	// Unquote causes quotes around values to be removed, so spaces around
+	// "  value  " or line breaks in "line1\nline2" can be stored. In double
+	// quoted values, \n, \r, \t, \xHH, \uXXXX, \UXXXXXXXX, \\ and \"
+	// escape sequences are interpreted and other backslashes are kept as
+	// is. Single quoted values are taken literally. Values are only
+	// unquoted if the closing quote ends the value, so `"a" b "c"` is kept
+	// as is. Output written with a WriteOptions.Quote style other than
+	// QuoteRaw should be loaded with Unquote. By default, values are loaded
+	// as found in the file, which is how Write writes them.
+	Unquote bool
+
+	// Lenient causes lines that cannot be parsed to be ignored instead of
+	// failing. If Comments is set, they are kept there like comments and
+	// written back unchanged, so files using unsupported extensions can be
+	// safely edited.
+	Lenient bool
+
+	// KeepEmptySections causes sections without values to be created, so
+	// their header is written back by Write. By default, a section only
+	// exists once it has values.
+	KeepEmptySections bool
+
+	// Conditional enables sections applying only to some platforms, such as
+	// "[server if os=linux]" whose values are merged into section server on
+	// Linux only, and skipped elsewhere. Conditions compare "os" or "arch"
+	// to the values of runtime.GOOS and runtime.GOARCH with = or !=, and
+	// can be combined with commas: "[if os=linux, arch!=arm64]" applies to
+	// the root section.
+	Conditional bool
+
+	// Includes enables include directives: a key named "include" causes the
+	// file named by its value to be loaded at this point, and
+	// "include-if-exists" does the same but ignores missing files. Relative
+	// paths are resolved from the directory of the including file when
+	// loading with LoadFile, and from the current directory otherwise. In
+	// paths, {hostname} is replaced with the host name and {env:NAME} with
+	// the value of environment variable NAME, so machine specific fragments
+	// can be pulled in with "include-if-exists = hosts/{hostname}.ini".
+	// Included files can include other files, up to 10 levels deep. An
+	// included file starts in the root section like any file, so values
+	// before its first section header are stored in root, even if the
+	// include directive appears within a section.
+	Includes bool
+
+	// RepeatedSections lists sections which can appear multiple times, such
+	// as "peer" in WireGuard configurations. Each header of such a section,
+	// written "[peer]" or "[[peer]]", starts a new instance stored as
+	// section "peer[0]", "peer[1]" and so on. See Ini.SectionsAll and
+	// WriteOptions.RepeatedSections. When loading into an Ini that already
+	// has instances, new ones are appended after them, so loading the same
+	// file twice yields each instance twice; remove the existing instances
+	// first to replace them.
+	RepeatedSections []string
+
+	// Template causes the source to be executed as a text/template before
+	// being parsed, with TemplateData as data and TemplateFuncs as extra
+	// functions, so values such as "port = {{ .BasePort }}" can be
+	// computed. Missing map keys are errors. The whole source is read in
+	// memory first.
+	Template      bool
+	TemplateData  any
+	TemplateFuncs template.FuncMap
+
+	// Limits protect against hostile input, such as uploaded files. When a
+	// limit is exceeded loading stops and a *LimitError is returned; values
+	// loaded until then are kept. Zero means no limit. Limits apply to the
+	// source and the files it includes as a whole, and include directives
+	// count as values.
+	MaxInputSize   int64 // bytes read from the source
+	MaxSections    int   // sections created by the source
+	MaxKeys        int   // values read from the source
+	MaxValueLength int   // length of a single value
+
+	dir   string     // directory of the file being loaded, for includes
+	depth int        // include nesting level
+	state *loadState // shared with included files
+}
+
+// loadState holds the counters checked against limits, shared by a load and
+// the loads of the files it includes
+type loadState struct {
+	keys     int   // values and include directives read
+	sections int   // sections created
+	left     int64 // bytes that can still be read, if MaxInputSize is set
+}
+
+// LimitError is returned when data being loaded exceeds one of the limits
+// set in LoadOptions.
+type LimitError struct {
+	Limit string // name of the limit, such as "MaxKeys"
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("failed to parse ini file: %s limit of %d exceeded", e.Limit, e.Max)
+}
+
+func (opts *LoadOptions) logger() *slog.Logger {
+	if opts == nil {
+		return nil
+	}
+	return opts.Logger
+}
+
+// limitReader returns a *LimitError once more than max bytes are read. The
+// error is kept, since the parser may fail on the truncated last line before
+// seeing it.
+type limitReader struct {
+	r     io.Reader
+	state *loadState // holds the bytes left, shared with included files
+	max   int64
+	err   error
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.state.left <= 0 {
+		// only fail if there actually is more data
+		var b [1]byte
+		if n, err := l.r.Read(b[:]); n == 0 {
+			return 0, err
+		}
+		l.err = &LimitError{Limit: "MaxInputSize", Max: l.max}
+		return 0, l.err
+	}
+	if int64(len(p)) > l.state.left {
+		p = p[:l.state.left]
+	}
+	n, err := l.r.Read(p)
+	l.state.left -= int64(n)
+	return n, err
+}
+
+// LoadFile will parse the file at path and merge loaded values. opts can be
+// nil.
+func (i Ini) LoadFile(path string, opts *LoadOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if log := opts.logger(); log != nil {
+		log.Debug("ini: opened file", "path", path)
+	}
+
+	if opts != nil && opts.Includes {
+		sub := *opts
+		sub.dir = filepath.Dir(path)
+		opts = &sub
+	}
+
+	return i.LoadWithOptions(f, opts)
+}
+
+// execTemplate reads source and returns the result of its execution as a
+// template
+func (opts *LoadOptions) execTemplate(source io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.New("ini").Funcs(opts.TemplateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ini template: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, opts.TemplateData); err != nil {
+		return nil, fmt.Errorf("failed to execute ini template: %w", err)
+	}
+	return buf, nil
+}
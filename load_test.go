@@ -0,0 +1,141 @@
+package ini_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestLoadLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	opts := &ini.LoadOptions{Logger: slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+
+	i := ini.New()
+	i.Set("section", "var1", "old")
+	err := i.LoadWithOptions(strings.NewReader("[section]\nvar1=new\nvar2=a\nvar2=b\n"), opts)
+	if err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{"ini: parsed section", "ini: overriding key", "ini: duplicate key"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing log event %q in %s", s, out)
+		}
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	src := "a=1\n[s1]\nb=2\nc=3\n[s2]\nd=4444\n"
+
+	tests := []struct {
+		opts  ini.LoadOptions
+		limit string
+	}{
+		{ini.LoadOptions{MaxInputSize: 10}, "MaxInputSize"},
+		{ini.LoadOptions{MaxSections: 2}, "MaxSections"},
+		{ini.LoadOptions{MaxKeys: 3}, "MaxKeys"},
+		{ini.LoadOptions{MaxValueLength: 3}, "MaxValueLength"},
+		{ini.LoadOptions{MaxInputSize: int64(len(src)), MaxSections: 3, MaxKeys: 4, MaxValueLength: 4}, ""},
+	}
+
+	for _, test := range tests {
+		err := ini.New().LoadWithOptions(strings.NewReader(src), &test.opts)
+		var lerr *ini.LimitError
+		if test.limit == "" {
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+		} else if !errors.As(err, &lerr) || lerr.Limit != test.limit {
+			t.Errorf("expected %s error, got %v", test.limit, err)
+		}
+	}
+}
+
+func TestLoadKeepEmptySections(t *testing.T) {
+	src := "a=1\n\n[empty]\n\n[s]\nb=2\n\n"
+
+	i := ini.New()
+	i.Load(strings.NewReader(src))
+	if _, ok := i["empty"]; ok {
+		t.Errorf("empty section created by default")
+	}
+
+	i = ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{KeepEmptySections: true}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	buf := &bytes.Buffer{}
+	i.Write(buf)
+	if buf.String() != src {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	i.UnsetKeep("s", "b")
+	i.AddSection("other")
+	buf.Reset()
+	i.Write(buf)
+	if buf.String() != "a=1\n\n[empty]\n\n[other]\n\n[s]\n\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	src := "[server]\nport = {{ .BasePort }}\nadmin = {{ add .BasePort 1 }}\nname = {{ upper \"web\" }}\n"
+	opts := &ini.LoadOptions{
+		Template:     true,
+		TemplateData: map[string]int{"BasePort": 8000},
+		TemplateFuncs: template.FuncMap{
+			"add":   func(a, b int) int { return a + b },
+			"upper": strings.ToUpper,
+		},
+	}
+
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), opts); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if d := i.Diff(ini.MustParse("[server]\nport=8000\nadmin=8001\nname=WEB\n")); len(d) != 0 {
+		t.Errorf("unexpected values: %v", d)
+	}
+
+	if err := ini.New().LoadWithOptions(strings.NewReader("port={{ .Missing }}\n"), opts); err == nil {
+		t.Errorf("expected an error for a missing key")
+	}
+}
+
+func TestLoadConditional(t *testing.T) {
+	src := "a=1\n[server]\npath=/default\n" +
+		"[server if os=" + runtime.GOOS + "]\npath=/native\n" +
+		"[server if os!=" + runtime.GOOS + "]\npath=/other\nextra=1\n" +
+		"[if os=" + runtime.GOOS + ", arch=" + runtime.GOARCH + "]\nb=2\n" +
+		"[if arch=none]\nc=3\n" +
+		"[gif if os=none]\nd=4\n" +
+		"[motif if os=" + runtime.GOOS + "]\ne=5\n" +
+		"[elif]\nf=6\n"
+
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{Conditional: true}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if d := i.Diff(ini.MustParse("a=1\nb=2\n[server]\npath=/native\n[motif]\ne=5\n[elif]\nf=6\n")); len(d) != 0 {
+		t.Errorf("unexpected values: %v", d)
+	}
+
+	if err := ini.New().LoadWithOptions(strings.NewReader("[if color=red]\na=1\n"), &ini.LoadOptions{Conditional: true}); err == nil {
+		t.Errorf("expected an error for an unknown condition")
+	}
+
+	// without the option, headers are taken literally
+	i = ini.New()
+	i.Load(strings.NewReader("[if os=none]\na=1\n"))
+	if _, ok := i.Get("if os=none", "a"); !ok {
+		t.Errorf("conditional section applied without the option")
+	}
+}
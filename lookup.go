@@ -0,0 +1,174 @@
+package ini
+
+import "strings"
+
+// lookup holds settings used by IniSafe.Get to find values missing from
+// their section. It is never modified once stored, changes are made to a
+// copy.
+type lookup struct {
+	rootFallback bool
+	defaults     Ini // registered with SetDefaults
+	fallback     Ini // attached with WithDefaults
+
+	keyAlias map[string]string   // "section\x00alias" to canonical key
+	keyNames map[string][]string // "section\x00key" to aliases of key
+
+	sectionAlias map[string]string   // alias to canonical section
+	sectionNames map[string][]string // section to its aliases
+}
+
+// sections returns the names under which the values of section may be
+// stored, the canonical name first
+func (l *lookup) sections(section string) []string {
+	if c, ok := l.sectionAlias[section]; ok {
+		section = c
+	}
+	if aliases, ok := l.sectionNames[section]; ok {
+		return append([]string{section}, aliases...)
+	}
+	return []string{section}
+}
+
+// keys returns the names under which the value of key may be stored, the
+// canonical name first
+func (l *lookup) keys(section, key string) []string {
+	if c, ok := l.keyAlias[section+"\x00"+key]; ok {
+		key = c
+	}
+	if aliases, ok := l.keyNames[section+"\x00"+key]; ok {
+		return append([]string{key}, aliases...)
+	}
+	return []string{key}
+}
+
+// get returns the value of a key, with lowercase section and key names.
+// Values are looked up in data, then in defaults set with SetDefaults, then
+// in the Ini attached with WithDefaults. With the root fallback, the root
+// section is only searched the same way once all these failed.
+func (l *lookup) get(data Ini, section, key string) (string, bool) {
+	sections := l.sections(section)
+	section = sections[0]
+	keys := l.keys(section, key)
+	sources := []Ini{data, l.defaults, l.fallback}
+	for _, src := range sources {
+		for _, n := range sections {
+			for _, k := range keys {
+				if v, ok := src[n][k]; ok {
+					return v, true
+				}
+			}
+		}
+	}
+	if l.rootFallback && section != "root" {
+		for _, src := range sources {
+			if v, ok := src["root"][keys[0]]; ok {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// updateLookup calls fn with a copy of the lookup settings, and stores it
+func (i *IniSafe) updateLookup(fn func(l *lookup)) {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	l := &lookup{}
+	if cur := i.lookup.Load(); cur != nil {
+		*l = *cur
+	}
+	fn(l)
+	i.lookup.Store(l)
+}
+
+// SetRootFallback enables (or disables) looking up keys missing from a
+// section in the root section, so global values apply as defaults to all
+// sections. Only Get is affected.
+func (i *IniSafe) SetRootFallback(enabled bool) {
+	i.updateLookup(func(l *lookup) { l.rootFallback = enabled })
+}
+
+// SetDefaults registers default values for a section, returned by Get for
+// keys absent from the data. It replaces any defaults previously registered
+// for the section; nil removes them. Defaults are never written or saved.
+func (i *IniSafe) SetDefaults(section string, defaults map[string]string) {
+	i.updateLookup(func(l *lookup) {
+		d := make(Ini, len(l.defaults)+1)
+		for n, s := range l.defaults {
+			d[n] = s
+		}
+		d.SetSection(section, defaults)
+		l.defaults = d
+	})
+}
+
+// WithDefaults attaches a copy of defaults, such as loaded from a shipped
+// defaults.ini, as a fallback for values missing from both the data and
+// SetDefaults. It replaces any previously attached defaults; nil removes
+// them. It returns i, so it can be chained with NewSafe.
+func (i *IniSafe) WithDefaults(defaults Ini) *IniSafe {
+	defaults = defaults.clone()
+	i.updateLookup(func(l *lookup) { l.fallback = defaults })
+	return i
+}
+
+// RegisterAlias declares alias as a former name of the canonical key of a
+// section, so files written before a setting was renamed keep working: Get
+// on either name returns the value stored under the canonical name or, if
+// missing, under the alias. Values are stored under the name they are set
+// with.
+func (i *IniSafe) RegisterAlias(section, alias, canonical string) {
+	section = strings.ToLower(section)
+	alias = strings.ToLower(alias)
+	canonical = strings.ToLower(canonical)
+
+	i.updateLookup(func(l *lookup) {
+		keyAlias := make(map[string]string, len(l.keyAlias)+1)
+		for k, v := range l.keyAlias {
+			keyAlias[k] = v
+		}
+		keyAlias[section+"\x00"+alias] = canonical
+		l.keyAlias = keyAlias
+
+		keyNames := make(map[string][]string, len(l.keyNames)+1)
+		for k, v := range l.keyNames {
+			keyNames[k] = v
+		}
+		n := section + "\x00" + canonical
+		keyNames[n] = appendCopy(keyNames[n], alias)
+		l.keyNames = keyNames
+	})
+}
+
+// RegisterSectionAlias declares alias as a former name of the canonical
+// section, such as "mysql" for "database". Get on either section returns
+// the value stored in the canonical section or, if missing, in the alias
+// section. Key aliases are registered with the canonical section name.
+func (i *IniSafe) RegisterSectionAlias(alias, canonical string) {
+	alias = strings.ToLower(alias)
+	canonical = strings.ToLower(canonical)
+
+	i.updateLookup(func(l *lookup) {
+		sectionAlias := make(map[string]string, len(l.sectionAlias)+1)
+		for k, v := range l.sectionAlias {
+			sectionAlias[k] = v
+		}
+		sectionAlias[alias] = canonical
+		l.sectionAlias = sectionAlias
+
+		sectionNames := make(map[string][]string, len(l.sectionNames)+1)
+		for k, v := range l.sectionNames {
+			sectionNames[k] = v
+		}
+		sectionNames[canonical] = appendCopy(sectionNames[canonical], alias)
+		l.sectionNames = sectionNames
+	})
+}
+
+// appendCopy appends v to a copy of s, leaving s unchanged
+func appendCopy(s []string, v string) []string {
+	res := make([]string, len(s), len(s)+1)
+	copy(res, s)
+	return append(res, v)
+}
@@ -0,0 +1,124 @@
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafeRootFallback(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("timeout=30\nlevel=info\n[web]\nlevel=debug\n"))
+
+	if _, ok := i.Get("web", "timeout"); ok {
+		t.Errorf("unexpected fallback while disabled")
+	}
+
+	i.SetRootFallback(true)
+	if v, _ := i.Get("Web", "timeout"); v != "30" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if v, _ := i.Get("web", "level"); v != "debug" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if v, _ := i.Get("other", "level"); v != "info" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if _, ok := i.Get("web", "missing"); ok {
+		t.Errorf("unexpected value for a missing key")
+	}
+
+	// defaults of the section take precedence over root values
+	i.Load(strings.NewReader("port=80\n"))
+	i.SetDefaults("db", map[string]string{"port": "5432"})
+	i.WithDefaults(ini.MustParse("[cache]\nport=6379\n"))
+	if v, _ := i.Get("db", "port"); v != "5432" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if v, _ := i.Get("cache", "port"); v != "6379" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if v, _ := i.Get("web", "port"); v != "80" {
+		t.Errorf("unexpected value %q", v)
+	}
+}
+
+func TestIniSafeSetDefaults(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("[db]\nhost=db.example.com\n"))
+
+	i.SetDefaults("DB", map[string]string{"Host": "localhost", "port": "5432"})
+	if v, _ := i.Get("db", "host"); v != "db.example.com" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if v, _ := i.Get("db", "port"); v != "5432" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if _, ok := i.Snapshot().Get("db", "port"); ok {
+		t.Errorf("defaults are part of the data")
+	}
+
+	i.SetDefaults("db", nil)
+	if _, ok := i.Get("db", "port"); ok {
+		t.Errorf("defaults not removed")
+	}
+}
+
+func TestIniSafeWithDefaults(t *testing.T) {
+	defaults := ini.MustParse("level=info\n[db]\nhost=localhost\nport=5432\n")
+	i := ini.NewSafe().WithDefaults(defaults)
+	i.Load(strings.NewReader("[db]\nhost=db.example.com\n"))
+	i.SetDefaults("db", map[string]string{"port": "6432"})
+
+	for _, test := range []struct{ section, key, value string }{
+		{"db", "host", "db.example.com"},
+		{"db", "port", "6432"},
+		{"root", "level", "info"},
+	} {
+		if v, _ := i.Get(test.section, test.key); v != test.value {
+			t.Errorf("unexpected value %q for %s.%s", v, test.section, test.key)
+		}
+	}
+
+	defaults.Set("root", "level", "debug")
+	if v, _ := i.Get("root", "level"); v != "info" {
+		t.Errorf("defaults were not copied")
+	}
+}
+
+func TestIniSafeRegisterAlias(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("[server]\nlisten_port=8080\n"))
+	i.RegisterAlias("Server", "Listen_Port", "port")
+
+	if v, _ := i.Get("server", "port"); v != "8080" {
+		t.Errorf("unexpected value %q for the canonical name", v)
+	}
+	if v, _ := i.Get("server", "listen_port"); v != "8080" {
+		t.Errorf("unexpected value %q for the alias", v)
+	}
+
+	i.Set("server", "port", "9090")
+	if v, _ := i.Get("server", "listen_port"); v != "9090" {
+		t.Errorf("canonical value does not take precedence, got %q", v)
+	}
+}
+
+func TestIniSafeRegisterSectionAlias(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("[mysql]\nhost=db.example.com\nuser=app\n[database]\nuser=admin\n"))
+	i.RegisterSectionAlias("MySQL", "database")
+	i.RegisterAlias("database", "user", "username")
+
+	for _, test := range []struct{ section, key, value string }{
+		{"database", "host", "db.example.com"},
+		{"mysql", "host", "db.example.com"},
+		{"database", "user", "admin"},
+		{"mysql", "username", "admin"},
+	} {
+		if v, _ := i.Get(test.section, test.key); v != test.value {
+			t.Errorf("unexpected value %q for %s.%s", v, test.section, test.key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FromMap returns a new Ini holding the values of m, which is indexed by
+// section then key. Section and key names are validated so the result can be
+// written and loaded back.
+func FromMap(m map[string]map[string]string) (Ini, error) {
+	res := New()
+
+	for n, s := range m {
+		if err := checkSection(n); err != nil {
+			return nil, err
+		}
+		for k, v := range s {
+			if err := checkKey(n, k); err != nil {
+				return nil, err
+			}
+			res.Set(n, k, v)
+		}
+	}
+
+	return res, nil
+}
+
+// FromFlatMap returns a new Ini holding the values of m, using the same
+// "section.key" naming as Flatten.
+func FromFlatMap(m map[string]string) (Ini, error) {
+	res := Unflatten(m)
+
+	for n, s := range res {
+		if err := checkSection(n); err != nil {
+			return nil, err
+		}
+		for k := range s {
+			if err := checkKey(n, k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// characters rejected by default in section and key names, in addition to
+// line breaks
+const (
+	invalidSectionChars = "[]"
+	invalidKeyChars     = "="
+)
+
+func checkSection(section string) error {
+	return checkSectionChars(section, invalidSectionChars)
+}
+
+func checkKey(section, key string) error {
+	return checkKeyChars(section, key, invalidKeyChars)
+}
+
+func checkSectionChars(section, chars string) error {
+	if section == "" || section != strings.TrimSpace(section) || strings.ContainsAny(section, "\r\n") || strings.ContainsAny(section, chars) {
+		return fmt.Errorf("invalid section name %q", section)
+	}
+	return nil
+}
+
+func checkKeyChars(section, key, chars string) error {
+	if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key, "\r\n") || strings.ContainsAny(key, chars) || key[0] == ';' || key[0] == '[' {
+		return fmt.Errorf("invalid key name %q in section %s", key, section)
+	}
+	return nil
+}
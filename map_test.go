@@ -0,0 +1,24 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestFromMap(t *testing.T) {
+	res, err := ini.FromMap(map[string]map[string]string{"Section": {"Var1": "value1"}})
+	if err != nil {
+		t.Fatalf("failed to build ini: %s", err)
+	}
+	if v, ok := res.Get("section", "var1"); !ok || v != "value1" {
+		t.Errorf("failed to get value section/var1, read %#v %#v", v, ok)
+	}
+
+	if _, err := ini.FromMap(map[string]map[string]string{"section": {"a=b": "c"}}); err == nil {
+		t.Errorf("expected error for invalid key name")
+	}
+	if _, err := ini.FromFlatMap(map[string]string{"[bad].key": "c"}); err == nil {
+		t.Errorf("expected error for invalid section name")
+	}
+}
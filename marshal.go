@@ -0,0 +1,226 @@
+package ini
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal returns a new Ini holding the values of the struct v, or of the
+// struct pointed to by v, see Encode.
+func Marshal(v any) (Ini, error) {
+	return MarshalWithOptions(v, nil)
+}
+
+// MarshalWithOptions is like Marshal, using the given options. opts can be
+// nil.
+func MarshalWithOptions(v any, opts *EncodeOptions) (Ini, error) {
+	i := New()
+	if err := i.EncodeWithOptions(v, opts); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// EncodeOptions holds settings used by EncodeWithOptions and
+// EncodeSectionWithOptions.
+type EncodeOptions struct {
+	// NameMapper returns the name of the key or section matching a field
+	// without a name in its tag. By default the field name is used.
+	NameMapper NameMapper
+}
+
+// Encode stores the fields of the struct v, or of the struct pointed to by
+// v, in the ini. This is the reverse of Decode: fields are stored as keys of
+// the root section and fields holding a struct as sections or sub-sections,
+// using the same names and formats. Slices are stored as comma separated
+// values, and types implementing encoding.TextMarshaler as their text form.
+//
+// Fields tagged with the omitempty option, such as `ini:"port,omitempty"`,
+// are not stored if they hold the zero value of their type or an empty
+// slice, so only settings that differ from the defaults are written.
+// Sections where all fields are omitted are not created. Nil pointers are
+// never stored.
+func (i Ini) Encode(v any) error {
+	return i.EncodeWithOptions(v, nil)
+}
+
+// EncodeWithOptions stores the fields of v like Encode, using the given
+// options. opts can be nil.
+func (i Ini) EncodeWithOptions(v any, opts *EncodeOptions) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	m := &marshaler{i: i, opts: opts}
+	return m.encodeStruct("root", rv)
+}
+
+// EncodeSection stores the fields of the struct v, or of the struct pointed
+// to by v, in a section, see Encode.
+func (i Ini) EncodeSection(section string, v any) error {
+	return i.EncodeSectionWithOptions(section, v, nil)
+}
+
+// EncodeSectionWithOptions stores the fields of v in a section like
+// EncodeSection, using the given options. opts can be nil.
+func (i Ini) EncodeSectionWithOptions(section string, v any, opts *EncodeOptions) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	m := &marshaler{i: i, opts: opts}
+	return m.encodeStruct(strings.ToLower(section), rv)
+}
+
+// structValue returns the struct v or pointed to by v
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("ini: cannot encode %T, need a struct", v)
+	}
+	return rv, nil
+}
+
+// marshaler holds the state of an encoding operation
+type marshaler struct {
+	i    Ini
+	opts *EncodeOptions
+}
+
+func (m *marshaler) fieldName(f reflect.StructField) string {
+	if m.opts == nil {
+		return fieldName(f, nil)
+	}
+	return fieldName(f, m.opts.NameMapper)
+}
+
+// encodeStruct stores the fields of rv in a section
+func (m *marshaler) encodeStruct(section string, rv reflect.Value) error {
+	t := rv.Type()
+	for n := 0; n < t.NumField(); n++ {
+		f := t.Field(n)
+		if isEmbedded(f) {
+			if err := m.encodeNested(section, rv.Field(n)); err != nil {
+				return err
+			}
+			continue
+		}
+		name := m.fieldName(f)
+		if name == "" {
+			continue
+		}
+		if isSectionField(f.Type) {
+			if err := m.encodeNested(subSection(section, name), rv.Field(n)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := m.encodeKey(section, name, f, rv.Field(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeNested stores fv, a struct or a pointer to a struct, in a section.
+// Nil pointers are skipped.
+func (m *marshaler) encodeNested(section string, fv reflect.Value) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	return m.encodeStruct(section, fv)
+}
+
+// encodeKey stores the value of fv, unless it is a nil pointer or it is
+// empty and f has the omitempty option
+func (m *marshaler) encodeKey(section, key string, f reflect.StructField, fv reflect.Value) error {
+	if fv.Kind() == reflect.Pointer && fv.IsNil() {
+		return nil
+	}
+	if hasOption(f, "omitempty") && isEmptyValue(fv) {
+		return nil
+	}
+	s, err := formatValue(fv, f.Tag.Get("layout"))
+	if err != nil {
+		return &KeyError{Section: section, Key: key, Err: err}
+	}
+	m.i.Set(section, key, s)
+	return nil
+}
+
+// isEmptyValue returns true if fv is omitted by the omitempty option
+func isEmptyValue(fv reflect.Value) bool {
+	if fv.Kind() == reflect.Slice {
+		return fv.Len() == 0
+	}
+	return fv.IsZero()
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// formatValue returns the text form of fv, which is the reverse of setValue
+func formatValue(fv reflect.Value, layout string) (string, error) {
+	if layout != "" {
+		switch {
+		case fv.Type() == timeType:
+			return fv.Interface().(time.Time).Format(layout), nil
+		case fv.Kind() == reflect.Pointer && fv.Type().Elem() == timeType && !fv.IsNil():
+			// *time.Time would otherwise be formatted by MarshalText
+			return formatValue(fv.Elem(), layout)
+		}
+	}
+	if fv.Type().Implements(textMarshalerType) {
+		b, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(textMarshalerType) {
+		b, err := fv.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	if fv.Type() == durationType {
+		return time.Duration(fv.Int()).String(), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Pointer:
+		if fv.IsNil() {
+			return "", nil
+		}
+		return formatValue(fv.Elem(), layout)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(fv.Bytes()), nil
+		}
+		parts := make([]string, fv.Len())
+		for n := range parts {
+			s, err := formatValue(fv.Index(n), layout)
+			if err != nil {
+				return "", err
+			}
+			parts[n] = s
+		}
+		return strings.Join(parts, ","), nil
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", fv.Type())
+}
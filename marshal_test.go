@@ -0,0 +1,115 @@
+package ini_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestMarshal(t *testing.T) {
+	type Server struct {
+		Host  string `ini:",omitempty"`
+		Port  uint16 `ini:"port,omitempty"`
+		Debug bool
+		Tags  []string `ini:",omitempty"`
+	}
+	type Config struct {
+		Name    string
+		Workers int `ini:"worker_count,omitempty"`
+		Ratio   float64
+		Addr    netip.Addr
+		Ports   []int
+		Server  Server
+		Admin   Server `ini:"admin_server"`
+		Skipped struct {
+			Value string `ini:",omitempty"`
+		}
+		Ignored string `ini:"-"`
+	}
+
+	cfg := Config{
+		Name:    "test",
+		Ratio:   0.5,
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Ports:   []int{80, 443},
+		Server:  Server{Host: "localhost", Port: 8080, Tags: []string{"a", "b"}},
+		Ignored: "x",
+	}
+	i, err := ini.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+
+	expect := "addr=10.0.0.1\nname=test\nports=80,443\nratio=0.5\n\n[admin_server]\ndebug=false\n\n[server]\ndebug=false\nhost=localhost\nport=8080\ntags=a,b\n\n"
+	if s := i.String(); s != expect {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+
+	var back Config
+	if err := i.Decode(&back); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if back.Name != cfg.Name || back.Addr != cfg.Addr || back.Server.Port != 8080 || len(back.Server.Tags) != 2 {
+		t.Errorf("unexpected decoded value %+v", back)
+	}
+
+	if _, err := ini.Marshal(map[string]string{}); err == nil {
+		t.Errorf("expected an error marshaling a map")
+	}
+}
+
+func TestMarshalTime(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration
+		Delays  []time.Duration
+		Created time.Time
+		Expires time.Time `layout:"2006-01-02"`
+	}
+
+	i := ini.MustParse("timeout=1m30s\ndelays=1s, 500ms\ncreated=2024-03-01T10:00:00Z\nexpires=2025-12-31\n")
+	var cfg Config
+	if err := i.Decode(&cfg); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if cfg.Timeout != 90*time.Second || len(cfg.Delays) != 2 || cfg.Delays[1] != 500*time.Millisecond {
+		t.Errorf("unexpected durations %v %v", cfg.Timeout, cfg.Delays)
+	}
+	if !cfg.Created.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) || !cfg.Expires.Equal(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected times %v %v", cfg.Created, cfg.Expires)
+	}
+
+	out, err := ini.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if s := out.String(); s != "created=2024-03-01T10:00:00Z\ndelays=1s,500ms\nexpires=2025-12-31\ntimeout=1m30s\n\n" {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+
+	i.Set("root", "expires", "2025-12-31T00:00:00Z")
+	if err := i.Decode(&cfg); err == nil {
+		t.Errorf("expected an error for a value not matching the layout")
+	}
+
+	// the layout applies through pointers and slices
+	type Dates struct {
+		Start    *time.Time  `layout:"2006-01-02"`
+		Holidays []time.Time `layout:"2006-01-02"`
+	}
+	var dates Dates
+	if err := ini.MustParse("start=2025-01-06\nholidays=2025-01-01, 2025-12-25\n").Decode(&dates); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if dates.Start == nil || !dates.Start.Equal(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) || len(dates.Holidays) != 2 || dates.Holidays[1].Month() != time.December {
+		t.Errorf("unexpected dates %+v", dates)
+	}
+	out, err = ini.Marshal(dates)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if s := out.String(); s != "holidays=2025-01-01,2025-12-25\nstart=2025-01-06\n\n" {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
@@ -0,0 +1,44 @@
+package ini
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMapper returns the name of the key or section matching a struct field
+// name, such as "max_conns" for "MaxConns". It is used by DecodeWithOptions
+// and EncodeWithOptions for fields without a name in their tag. Since names
+// are not case sensitive, the result is lowercased.
+type NameMapper func(field string) string
+
+var (
+	// Verbatim uses field names as is, which is the default.
+	Verbatim NameMapper = func(field string) string { return field }
+
+	// SnakeCase separates words of field names with underscores, turning
+	// "MaxConns" and "HTTPPort" into "max_conns" and "http_port".
+	SnakeCase NameMapper = func(field string) string { return splitWords(field, '_') }
+
+	// KebabCase separates words of field names with dashes, turning
+	// "MaxConns" and "HTTPPort" into "max-conns" and "http-port".
+	KebabCase NameMapper = func(field string) string { return splitWords(field, '-') }
+)
+
+// splitWords returns the lowercase words of a CamelCase name separated by
+// sep. A run of uppercase letters is one word, except for its last letter
+// when followed by a lowercase letter.
+func splitWords(name string, sep rune) string {
+	r := []rune(name)
+	var b strings.Builder
+	for n, c := range r {
+		if n > 0 && unicode.IsUpper(c) {
+			prev := r[n-1]
+			next := n+1 < len(r) && unicode.IsLower(r[n+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteRune(sep)
+			}
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
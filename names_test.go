@@ -0,0 +1,94 @@
+package ini_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestNameMapper(t *testing.T) {
+	tests := []struct {
+		in, snake, kebab string
+	}{
+		{"Name", "name", "name"},
+		{"MaxConns", "max_conns", "max-conns"},
+		{"HTTPPort", "http_port", "http-port"},
+		{"UserID", "user_id", "user-id"},
+		{"Retry2Delay", "retry2_delay", "retry2-delay"},
+	}
+	for _, test := range tests {
+		if s := ini.SnakeCase(test.in); s != test.snake {
+			t.Errorf("SnakeCase(%q) = %q, expected %q", test.in, s, test.snake)
+		}
+		if s := ini.KebabCase(test.in); s != test.kebab {
+			t.Errorf("KebabCase(%q) = %q, expected %q", test.in, s, test.kebab)
+		}
+	}
+}
+
+func TestDecodeNameMapper(t *testing.T) {
+	type Config struct {
+		MaxConns  int
+		HTTPPort  int `ini:"port"`
+		LogServer struct {
+			HostName string
+		}
+	}
+
+	i := ini.MustParse("max_conns=10\nport=80\n[log_server]\nhost_name=log\n")
+	var cfg Config
+	if err := i.DecodeWithOptions(&cfg, &ini.DecodeOptions{NameMapper: ini.SnakeCase}); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if cfg.MaxConns != 10 || cfg.HTTPPort != 80 || cfg.LogServer.HostName != "log" {
+		t.Errorf("unexpected result %+v", cfg)
+	}
+
+	out, err := ini.MarshalWithOptions(cfg, &ini.EncodeOptions{NameMapper: ini.KebabCase})
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if s := out.String(); s != "max-conns=10\nport=80\n\n[log-server]\nhost-name=log\n\n" {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
+
+func TestSectionNameMapper(t *testing.T) {
+	type Upstream struct {
+		HostName string
+		MaxConns int
+	}
+	opts := &ini.DecodeOptions{NameMapper: ini.SnakeCase, DisallowUnknownKeys: true}
+
+	i := ini.MustParse("[upstream.api]\nhost_name=api1\nmax_conns=4\n[upstream.web]\nhost_name=web1\n[other]\nfoo=bar\n")
+	var api Upstream
+	if err := i.DecodeSectionWithOptions("upstream.api", &api, opts); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if api.HostName != "api1" || api.MaxConns != 4 {
+		t.Errorf("unexpected result %+v", api)
+	}
+
+	var m map[string]Upstream
+	if err := i.DecodePrefixWithOptions("upstream.", &m, opts); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+	if m["web"].HostName != "web1" || m["api"].MaxConns != 4 {
+		t.Errorf("unexpected result %+v", m)
+	}
+
+	i.Set("upstream.web", "typo", "1")
+	var ue *ini.UnknownKeysError
+	if err := i.DecodePrefixWithOptions("upstream.", &m, opts); !errors.As(err, &ue) || len(ue.Keys) != 1 || ue.Keys[0] != "upstream.web.typo" {
+		t.Errorf("expected an unknown key error, got %v", err)
+	}
+
+	out := ini.New()
+	if err := out.EncodeSectionWithOptions("upstream.api", api, &ini.EncodeOptions{NameMapper: ini.KebabCase}); err != nil {
+		t.Fatalf("failed to encode: %s", err)
+	}
+	if s := out.String(); s != "[upstream.api]\nhost-name=api1\nmax-conns=4\n\n" {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
@@ -0,0 +1,29 @@
+package ini_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"server10", "server2", "server1", "server02", "db", "server", "server2a"}
+	sort.Slice(names, func(a, b int) bool { return ini.NaturalLess(names[a], names[b]) })
+
+	if s := strings.Join(names, ","); s != "db,server,server1,server2,server2a,server02,server10" {
+		t.Errorf("unexpected order %s", s)
+	}
+}
+
+func TestSectionsSorted(t *testing.T) {
+	i := ini.MustParse("a=1\n[server10]\nb=2\nc=3\n[server2]\nb=2\n")
+
+	if s := strings.Join(i.SectionsSorted(), ","); s != "root,server10,server2" {
+		t.Errorf("unexpected sections %s", s)
+	}
+	if s := strings.Join(i.KeysSorted("Server10"), ","); s != "b,c" {
+		t.Errorf("unexpected keys %s", s)
+	}
+}
@@ -0,0 +1,81 @@
+package ini
+
+import "strings"
+
+// NestedMap returns the values of a section as a tree, with dotted keys such
+// as "a.b.c" split into nested maps: the value of "a.b.c" is found at
+// m["a"]["b"]["c"]. Values are strings and nodes are map[string]any. When a
+// key is both a value and the prefix of other keys, such as "a" and "a.b",
+// its value is stored under the empty key of the node. It returns nil if the
+// section does not exist.
+func (i Ini) NestedMap(section string) map[string]any {
+	s, ok := i[strings.ToLower(section)]
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string]any)
+	// sorted, so values are set before their children
+	for _, k := range sortedKeys(s) {
+		node := res
+		parts := strings.Split(k, ".")
+		for _, p := range parts[:len(parts)-1] {
+			switch next := node[p].(type) {
+			case map[string]any:
+				node = next
+			case string:
+				// value and prefix
+				m := map[string]any{"": next}
+				node[p] = m
+				node = m
+			default:
+				m := make(map[string]any)
+				node[p] = m
+				node = m
+			}
+		}
+
+		last := parts[len(parts)-1]
+		if m, ok := node[last].(map[string]any); ok {
+			m[""] = s[k]
+		} else {
+			node[last] = s[k]
+		}
+	}
+
+	return res
+}
+
+// GetNested returns the value or subtree found at a dotted path in a section,
+// as returned by NestedMap: a string for a value, or a map[string]any for the
+// keys sharing the path as prefix. For example with keys "db.host" and
+// "db.port", GetNested(section, "db") returns a map holding "host" and "port".
+func (i Ini) GetNested(section, path string) (any, bool) {
+	s := i[strings.ToLower(section)]
+	path = strings.ToLower(path)
+
+	if v, ok := s[path]; ok && !hasPrefixedKey(s, path+".") {
+		return v, true
+	}
+
+	var node any = i.NestedMap(section)
+	for _, p := range strings.Split(path, ".") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[p]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func hasPrefixedKey(s map[string]string, prefix string) bool {
+	for k := range s {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package ini_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestNestedMap(t *testing.T) {
+	i := ini.MustParse("[app]\nname=test\ndb.host=localhost\ndb.port=5432\ndb.pool.max=10\nlog=info\nlog.file=app.log\n")
+
+	expect := map[string]any{
+		"name": "test",
+		"db": map[string]any{
+			"host": "localhost",
+			"port": "5432",
+			"pool": map[string]any{"max": "10"},
+		},
+		"log": map[string]any{"": "info", "file": "app.log"},
+	}
+	if m := i.NestedMap("App"); !reflect.DeepEqual(m, expect) {
+		t.Errorf("unexpected map %v", m)
+	}
+
+	if v, ok := i.GetNested("app", "db.pool.max"); !ok || v != "10" {
+		t.Errorf("unexpected value %v", v)
+	}
+	if v, ok := i.GetNested("app", "db"); !ok || !reflect.DeepEqual(v, expect["db"]) {
+		t.Errorf("unexpected subtree %v", v)
+	}
+	if v, ok := i.GetNested("app", "log"); !ok || !reflect.DeepEqual(v, expect["log"]) {
+		t.Errorf("unexpected subtree %v", v)
+	}
+	if _, ok := i.GetNested("app", "db.host.x"); ok {
+		t.Errorf("unexpected value below a leaf")
+	}
+	if _, ok := i.GetNested("app", "missing"); ok {
+		t.Errorf("unexpected value for a missing path")
+	}
+}
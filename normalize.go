@@ -0,0 +1,56 @@
+package ini
+
+import "strings"
+
+// NormalizeOptions holds settings used by Normalize.
+type NormalizeOptions struct {
+	// PreserveCase keeps the case of section and key names instead of
+	// lowercasing them.
+	PreserveCase bool
+
+	// TrimValues removes spaces around values. Such spaces can only come
+	// from quoted values, so they are kept by default.
+	TrimValues bool
+}
+
+// Normalize returns a copy of the ini in canonical form, suitable for hashing
+// and comparison: section and key names are trimmed and lowercased, and empty
+// sections are dropped. Names that become identical are merged; for
+// duplicate keys the value of the alphabetically first original name wins.
+// Since Write sorts entries, two normalized Ini with the same values always
+// produce the same output. opts can be nil.
+func (i Ini) Normalize(opts *NormalizeOptions) Ini {
+	if opts == nil {
+		opts = &NormalizeOptions{}
+	}
+	name := func(s string) string {
+		s = strings.TrimSpace(s)
+		if !opts.PreserveCase {
+			s = strings.ToLower(s)
+		}
+		return s
+	}
+
+	res := New()
+	for _, n := range sortedKeys(i) {
+		s := i[n]
+		for _, k := range sortedKeys(s) {
+			v := s[k]
+			if opts.TrimValues {
+				v = strings.TrimSpace(v)
+			}
+
+			sn := name(n)
+			sub, ok := res[sn]
+			if !ok {
+				sub = make(map[string]string)
+				res[sn] = sub
+			}
+			if _, dup := sub[name(k)]; !dup {
+				sub[name(k)] = v
+			}
+		}
+	}
+
+	return res
+}
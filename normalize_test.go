@@ -0,0 +1,36 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestNormalize(t *testing.T) {
+	i := ini.Ini{
+		"root":     {"A": "1"},
+		" Server ": {"Host ": " localhost ", "host": "other"},
+		"server":   {"port": "80"},
+		"empty":    {},
+	}
+
+	res := i.Normalize(nil)
+	expect := mustParseUnquote("a=1\n[server]\nhost=\" localhost \"\nport=80\n")
+	if d := res.Diff(expect); len(d) != 0 {
+		t.Errorf("unexpected result: %v", d)
+	}
+	if _, ok := res["empty"]; ok {
+		t.Errorf("empty section not dropped")
+	}
+	if res.Hash() != expect.Hash() {
+		t.Errorf("hashes differ")
+	}
+
+	res = i.Normalize(&ini.NormalizeOptions{PreserveCase: true, TrimValues: true})
+	if v := res["Server"]["Host"]; v != "localhost" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if _, ok := res["root"]["A"]; !ok {
+		t.Errorf("case of key not preserved")
+	}
+}
@@ -0,0 +1,106 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Parser reads ini data and calls its callbacks for each element found,
+// without storing anything. It can process inputs of any size with constant
+// memory. Any callback can be nil, and parsing stops on the first error
+// returned by a callback.
+type Parser struct {
+	// OnSection is called for each section header, with the name of the
+	// section as written in the file.
+	OnSection func(name string, line int) error
+
+	// OnKeyValue is called for each value, with the section it belongs to
+	// ("root" before the first section header), and the key as written in
+	// the file. If Unquote is set, quotes around the value have been
+	// removed.
+	OnKeyValue func(section, key, value string, line int) error
+
+	// OnComment is called for each comment line, with the text following
+	// the ';' character.
+	OnComment func(text string, line int) error
+
+	// OnInvalid, if set, is called for lines that cannot be parsed, with the
+	// line as found in the input, instead of failing.
+	OnInvalid func(text string, line int) error
+
+	// Unquote causes quotes around values to be removed, see
+	// LoadOptions.Unquote. Otherwise values are passed as found, only
+	// trimmed of surrounding spaces.
+	Unquote bool
+}
+
+// Parse reads ini data from r until EOF.
+func (p *Parser) Parse(r io.Reader) error {
+	buf := scanBufPool.Get().(*[]byte)
+	defer scanBufPool.Put(buf)
+
+	s := bufio.NewScanner(r)
+	s.Buffer(*buf, maxLineSize)
+	section := "root"
+	lineNo := 0
+
+	for s.Scan() {
+		lineNo++
+		// work on the scanner's buffer and only allocate the strings passed
+		// to callbacks
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] == ';' {
+			if p.OnComment != nil {
+				if err := p.OnComment(string(line[1:]), lineNo); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = string(bytes.TrimSpace(line[1 : len(line)-1]))
+			if p.OnSection != nil {
+				if err := p.OnSection(section, lineNo); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		pos := bytes.IndexByte(line, '=')
+		if pos < 0 {
+			if p.OnInvalid != nil {
+				if err := p.OnInvalid(s.Text(), lineNo); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("failed to parse ini file on line %d: invalid line", lineNo)
+		}
+		if p.OnKeyValue == nil {
+			continue
+		}
+
+		// a single allocation holds both key and value
+		k := bytes.TrimSpace(line[:pos])
+		v := bytes.TrimSpace(line[pos+1:])
+		str := string(k) + string(v)
+
+		val := str[len(k):]
+		if p.Unquote {
+			val = unquoteValue(val)
+		}
+		if err := p.OnKeyValue(section, str[:len(k)], val, lineNo); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
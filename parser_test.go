@@ -0,0 +1,82 @@
+package ini_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestParser(t *testing.T) {
+	var events []string
+	p := &ini.Parser{
+		OnSection: func(name string, line int) error {
+			events = append(events, fmt.Sprintf("%d section %s", line, name))
+			return nil
+		},
+		OnKeyValue: func(section, key, value string, line int) error {
+			events = append(events, fmt.Sprintf("%d %s.%s=%s", line, section, key, value))
+			return nil
+		},
+		OnComment: func(text string, line int) error {
+			events = append(events, fmt.Sprintf("%d comment%s", line, text))
+			return nil
+		},
+		Unquote: true,
+	}
+
+	err := p.Parse(strings.NewReader("; test\na=1\n\n[Section]\nKey = \"quoted value\"\n"))
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	expect := []string{
+		"1 comment test",
+		"2 root.a=1",
+		"4 section Section",
+		"5 Section.Key=quoted value",
+	}
+	if strings.Join(events, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected events:\n%s", strings.Join(events, "\n"))
+	}
+}
+
+// benchData returns a few MB of ini data
+func benchData() []byte {
+	buf := &bytes.Buffer{}
+	for s := 0; s < 1000; s++ {
+		fmt.Fprintf(buf, "; section %d\n[section%d]\n", s, s)
+		for k := 0; k < 100; k++ {
+			fmt.Fprintf(buf, "key%d = some value for key %d\n", k, k)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkLoad(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		if err := ini.New().Load(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParser(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	p := &ini.Parser{}
+	for n := 0; n < b.N; n++ {
+		if err := p.Parse(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ini
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+)
+
+// PollFile loads the file at path, then reads it again every interval and
+// reloads the data (see Reload) whenever its contents hash differs. It does
+// not rely on file system notifications, so it works on network and
+// container file systems where those are unreliable.
+//
+// PollFile blocks until ctx is done, so it is usually run in its own
+// goroutine. onReload, if not nil, is called after each reload attempt with
+// the changes or the error encountered. Values are kept unchanged on error.
+func (i *IniSafe) PollFile(ctx context.Context, path string, interval time.Duration, onReload func([]Change, error)) error {
+	var last []byte
+
+	check := func() {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			if onReload != nil {
+				onReload(nil, err)
+			}
+			return
+		}
+
+		sum := sha256.Sum256(buf)
+		if bytes.Equal(sum[:], last) {
+			return
+		}
+
+		changes, err := i.Reload(bytes.NewReader(buf))
+		if err == nil {
+			last = sum[:]
+		}
+		if onReload != nil {
+			onReload(changes, err)
+		}
+	}
+
+	check()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			check()
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ini_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafePollFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ini")
+	if err := os.WriteFile(path, []byte("[db]\nhost=a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i := ini.NewSafe()
+	reloads := make(chan []ini.Change, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go i.PollFile(ctx, path, 10*time.Millisecond, func(c []ini.Change, err error) {
+		if err == nil {
+			reloads <- c
+		}
+	})
+
+	wait := func() []ini.Change {
+		t.Helper()
+		select {
+		case c := <-reloads:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for reload")
+			return nil
+		}
+	}
+
+	wait()
+	if v, _ := i.Get("db", "host"); v != "a" {
+		t.Errorf("unexpected value %#v", v)
+	}
+
+	// same size and content hash change only
+	if err := os.WriteFile(path, []byte("[db]\nhost=b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c := wait(); len(c) != 1 || c[0].New != "b" {
+		t.Errorf("unexpected changes %v", c)
+	}
+}
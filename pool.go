@@ -0,0 +1,39 @@
+package ini
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// bufferSize is the size of pooled buffers, matching bufio's default
+const bufferSize = 4096
+
+// maxLineSize is the maximum size of a line when parsing
+const maxLineSize = 1024 * 1024
+
+var scanBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, bufferSize)
+		return &buf
+	},
+}
+
+var writerPool = sync.Pool{
+	New: func() any {
+		return bufio.NewWriterSize(nil, bufferSize)
+	},
+}
+
+// getWriter returns a pooled bufio.Writer writing to w
+func getWriter(w io.Writer) *bufio.Writer {
+	b := writerPool.Get().(*bufio.Writer)
+	b.Reset(w)
+	return b
+}
+
+// putWriter returns b to the pool
+func putWriter(b *bufio.Writer) {
+	b.Reset(nil)
+	writerPool.Put(b)
+}
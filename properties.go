@@ -0,0 +1,160 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ToProperties returns the ini in java .properties format, using the same
+// "section.key" naming as Flatten.
+func (i Ini) ToProperties() []byte {
+	flat := i.Flatten()
+	buf := &bytes.Buffer{}
+
+	for _, k := range sortedKeys(flat) {
+		buf.WriteString(escapeProperty(k, true))
+		buf.WriteByte('=')
+		buf.WriteString(escapeProperty(flat[k], false))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// FromProperties parses data in java .properties format and returns the
+// matching Ini, splitting names as Unflatten does.
+func FromProperties(data []byte) (Ini, error) {
+	flat := make(map[string]string)
+	r := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+
+	for r.Scan() {
+		lineNo++
+		line := strings.TrimLeft(r.Text(), " \t\f")
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// an odd number of trailing backslashes continues on the next line
+		for continuesLine(line) && r.Scan() {
+			lineNo++
+			line = line[:len(line)-1] + strings.TrimLeft(r.Text(), " \t\f")
+		}
+
+		k, v, err := splitProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse properties on line %d: %w", lineNo, err)
+		}
+		flat[k] = v
+	}
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	return Unflatten(flat), nil
+}
+
+func continuesLine(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, "\\"))
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line into its unescaped key and value
+func splitProperty(line string) (string, string, error) {
+	// find the end of the key, which is the first unescaped separator
+	end := len(line)
+	for n := 0; n < len(line); n++ {
+		if line[n] == '\\' {
+			n++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[n]) >= 0 {
+			end = n
+			break
+		}
+	}
+
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if len(rest) > 0 && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	val, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return key, val, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for n := 0; n < len(s); n++ {
+		c := s[n]
+		if c != '\\' || n+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		n++
+		switch s[n] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if n+5 > len(s) {
+				return "", io.ErrUnexpectedEOF
+			}
+			r, err := strconv.ParseUint(s[n+1:n+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape: %w", err)
+			}
+			b.WriteRune(rune(r))
+			n += 4
+		default:
+			b.WriteByte(s[n])
+		}
+	}
+	return b.String(), nil
+}
+
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for n, c := range s {
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', ' ':
+			if isKey || n == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestProperties(t *testing.T) {
+	src := ini.New()
+	src.Set("root", "var1", " value with spaces")
+	src.Set("section", "path", "c:\\dir\nnext")
+
+	res, err := ini.FromProperties(src.ToProperties())
+	if err != nil {
+		t.Fatalf("failed to parse properties: %s", err)
+	}
+	for _, e := range [][2]string{{"root", "var1"}, {"section", "path"}} {
+		want, _ := src.Get(e[0], e[1])
+		if v, ok := res.Get(e[0], e[1]); !ok || v != want {
+			t.Errorf("failed to get value %s/%s, read %#v %#v", e[0], e[1], v, ok)
+		}
+	}
+
+	res, err = ini.FromProperties([]byte("# comment\ndb.host : localhost\ndb.name   test \\\n    db\nkey\\=name=\\u00e9\n"))
+	if err != nil {
+		t.Fatalf("failed to parse properties: %s", err)
+	}
+	expect := [][3]string{
+		{"db", "host", "localhost"},
+		{"db", "name", "test db"},
+		{"root", "key=name", "é"},
+	}
+	for _, e := range expect {
+		if v, ok := res.Get(e[0], e[1]); !ok || v != e[2] {
+			t.Errorf("failed to get value %s/%s, read %#v %#v", e[0], e[1], v, ok)
+		}
+	}
+}
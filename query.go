@@ -0,0 +1,125 @@
+package ini
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// GetPrefixed returns the values of a section whose key starts with prefix,
+// indexed by the rest of the key. For example with prefix "db.", the value
+// of "db.host" is returned as "host". It returns nil if no key matches.
+func (i Ini) GetPrefixed(section, prefix string) map[string]string {
+	prefix = strings.ToLower(prefix)
+
+	var res map[string]string
+	for k, v := range i[strings.ToLower(section)] {
+		if rest, ok := strings.CutPrefix(k, prefix); ok {
+			if res == nil {
+				res = make(map[string]string)
+			}
+			res[rest] = v
+		}
+	}
+	return res
+}
+
+// GetPrefixed returns the values of a section whose key starts with prefix,
+// see Ini.GetPrefixed.
+func (i *IniSafe) GetPrefixed(section, prefix string) map[string]string {
+	return i.snapshot().GetPrefixed(section, prefix)
+}
+
+// SectionsMatching returns the names of the sections matching a glob pattern
+// such as "server.*", in alphabetical order. The pattern syntax is the one of
+// path.Match, which returns an error for malformed patterns.
+func (i Ini) SectionsMatching(pattern string) ([]string, error) {
+	pattern = strings.ToLower(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, n := range sortedKeys(i) {
+		if ok, _ := path.Match(pattern, n); ok {
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+// SectionsMatching returns the names of the sections matching a glob
+// pattern, see Ini.SectionsMatching.
+func (i *IniSafe) SectionsMatching(pattern string) ([]string, error) {
+	return i.snapshot().SectionsMatching(pattern)
+}
+
+// Match is a value found by Search.
+type Match struct {
+	Section string
+	Key     string
+	Value   string
+}
+
+// Search returns the values whose key or value matches re, sorted by section
+// and key.
+func (i Ini) Search(re *regexp.Regexp) []Match {
+	var res []Match
+
+	for _, n := range sortedKeys(i) {
+		s := i[n]
+		for _, k := range sortedKeys(s) {
+			if re.MatchString(k) || re.MatchString(s[k]) {
+				res = append(res, Match{Section: n, Key: k, Value: s[k]})
+			}
+		}
+	}
+
+	return res
+}
+
+// Search returns the values whose key or value matches re, see Ini.Search.
+func (i *IniSafe) Search(re *regexp.Regexp) []Match {
+	return i.snapshot().Search(re)
+}
+
+// Walk calls fn for each value, in the order used by Write: root section
+// first, then sections and keys in alphabetical order. It stops and returns
+// the error returned by fn, if any.
+func (i Ini) Walk(fn func(section, key, value string) error) error {
+	for _, n := range i.sortedSections() {
+		s := i[n]
+		for _, k := range sortedKeys(s) {
+			if err := fn(n, k, s[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for each value, see Ini.Walk. Modifications made by fn are
+// not visible to the walk, which runs on a snapshot.
+func (i *IniSafe) Walk(fn func(section, key, value string) error) error {
+	return i.snapshot().Walk(fn)
+}
+
+// GetFirst looks up key in each of the given sections in order, such as a
+// host specific section, then an environment, then defaults, and returns the
+// first value found along with the (lowercase) section holding it.
+func (i Ini) GetFirst(key string, sections ...string) (value, section string, ok bool) {
+	key = strings.ToLower(key)
+	for _, n := range sections {
+		n = strings.ToLower(n)
+		if v, ok := i[n][key]; ok {
+			return v, n, true
+		}
+	}
+	return "", "", false
+}
+
+// GetFirst looks up key in each of the given sections in order, see
+// Ini.GetFirst.
+func (i *IniSafe) GetFirst(key string, sections ...string) (value, section string, ok bool) {
+	return i.snapshot().GetFirst(key, sections...)
+}
@@ -0,0 +1,102 @@
+package ini_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestGetPrefixed(t *testing.T) {
+	i := ini.MustParse("[app]\nname=test\ndb.host=localhost\nDB.Port=5432\ndbname=x\n")
+
+	res := i.GetPrefixed("APP", "Db.")
+	if len(res) != 2 || res["host"] != "localhost" || res["port"] != "5432" {
+		t.Errorf("unexpected result %v", res)
+	}
+
+	if res := i.GetPrefixed("app", "cache."); res != nil {
+		t.Errorf("unexpected result %v", res)
+	}
+}
+
+func TestSectionsMatching(t *testing.T) {
+	i := ini.MustParse("[server.b]\na=1\n[server.a]\na=1\n[servers]\na=1\n[client.a]\na=1\n")
+
+	res, err := i.SectionsMatching("Server.*")
+	if err != nil {
+		t.Fatalf("failed to match: %s", err)
+	}
+	if strings.Join(res, ",") != "server.a,server.b" {
+		t.Errorf("unexpected result %v", res)
+	}
+
+	if _, err := i.SectionsMatching("server.["); err == nil {
+		t.Errorf("expected an error for a malformed pattern")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	i := ini.MustParse("[db]\nhost=db1.example.com\nport=5432\n[cache]\nhost=cache.local\n[web]\nbackend=db1.example.com:8080\n")
+
+	res := i.Search(regexp.MustCompile(`db1\.example`))
+	expect := []ini.Match{
+		{Section: "db", Key: "host", Value: "db1.example.com"},
+		{Section: "web", Key: "backend", Value: "db1.example.com:8080"},
+	}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf("unexpected result %v", res)
+	}
+
+	if res := i.Search(regexp.MustCompile(`^port$`)); len(res) != 1 || res[0].Value != "5432" {
+		t.Errorf("unexpected result %v", res)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	i := ini.MustParse("z=1\na=2\n[b]\nx=3\n[a]\ny=4\n")
+
+	var res []string
+	err := i.Walk(func(section, key, value string) error {
+		res = append(res, section+"."+key+"="+value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if strings.Join(res, ",") != "root.a=2,root.z=1,a.y=4,b.x=3" {
+		t.Errorf("unexpected order %v", res)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = i.Walk(func(section, key, value string) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("walk did not stop on error: %v after %d calls", err, n)
+	}
+}
+
+func TestGetFirst(t *testing.T) {
+	i := ini.MustParse("[host.web1]\nport=8080\n[env.prod]\nport=80\nlevel=warn\n[defaults]\nport=8000\nlevel=info\ntimeout=30\n")
+
+	for _, test := range []struct{ key, value, section string }{
+		{"port", "8080", "host.web1"},
+		{"Level", "warn", "env.prod"},
+		{"timeout", "30", "defaults"},
+	} {
+		v, s, ok := i.GetFirst(test.key, "Host.Web1", "env.prod", "defaults")
+		if !ok || v != test.value || s != test.section {
+			t.Errorf("unexpected result for %s: %q from %q", test.key, v, s)
+		}
+	}
+
+	if _, _, ok := i.GetFirst("missing", "host.web1", "defaults"); ok {
+		t.Errorf("unexpected value for a missing key")
+	}
+}
@@ -0,0 +1,239 @@
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// needsQuote returns true if v cannot be written as is and read back
+func needsQuote(v string) bool {
+	if v == "" {
+		return false
+	}
+	if v != strings.TrimSpace(v) || isQuoted(v) {
+		return true
+	}
+	return hasControl(v, false)
+}
+
+// QuoteStyle controls how values are quoted when writing. Values are written
+// verbatim by default, as a plain Load reads them. Other styles produce
+// output meant to be loaded with LoadOptions.Unquote.
+type QuoteStyle int
+
+const (
+	QuoteRaw     QuoteStyle = iota // write values verbatim, fail only on line breaks
+	QuoteMinimal                   // double quotes only when needed
+	QuoteAlways                    // double quotes around every value
+	QuoteSingle                    // single quotes when needed, double quotes if the value has control characters
+	QuoteNever                     // never quote, fail if a value needs quotes
+)
+
+// quoteStyle returns v quoted according to style. If ascii is true, values
+// with non-ASCII characters are double quoted with these characters escaped.
+func quoteStyle(v string, style QuoteStyle, ascii bool) (string, error) {
+	if ascii && !isASCII(v) {
+		if style == QuoteNever || style == QuoteRaw {
+			return "", fmt.Errorf("value %q cannot be written as ASCII without quotes", v)
+		}
+		return quoteDouble(v, true), nil
+	}
+	switch style {
+	case QuoteRaw:
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("value %q contains a line break", v)
+		}
+		return v, nil
+	case QuoteAlways:
+		return doubleQuote(v), nil
+	case QuoteSingle:
+		if needsQuote(v) && !hasControl(v, true) && !strings.Contains(v, "'") {
+			return "'" + v + "'", nil
+		}
+	case QuoteNever:
+		if needsQuote(v) {
+			return "", fmt.Errorf("value %q cannot be written without quotes", v)
+		}
+		return v, nil
+	}
+	return quoteValue(v), nil
+}
+
+// hasControl returns true if v contains control characters. Tabs are
+// ignored if allowTab is true.
+func hasControl(v string, allowTab bool) bool {
+	for _, c := range []byte(v) {
+		if (c < 0x20 && (c != '\t' || !allowTab)) || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteValue returns v enclosed in double quotes if needed
+func quoteValue(v string) string {
+	if !needsQuote(v) {
+		return v
+	}
+	return doubleQuote(v)
+}
+
+// isASCII returns true if v only contains ASCII characters
+func isASCII(v string) bool {
+	for _, c := range []byte(v) {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// doubleQuote returns v enclosed in double quotes with special characters
+// escaped
+func doubleQuote(v string) string {
+	return quoteDouble(v, false)
+}
+
+// quoteDouble returns v enclosed in double quotes with special characters
+// escaped. If ascii is true, non-ASCII characters are escaped as \uXXXX (or
+// \UXXXXXXXX beyond the basic multilingual plane), and invalid UTF-8 bytes as
+// \xHH.
+func quoteDouble(v string, ascii bool) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for n := 0; n < len(v); n++ {
+		c := v[n]
+		if ascii && c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(v[n:])
+			switch {
+			case r == utf8.RuneError && size == 1:
+				fmt.Fprintf(&b, `\x%02x`, c)
+			case r > 0xffff:
+				fmt.Fprintf(&b, `\U%08x`, r)
+			default:
+				fmt.Fprintf(&b, `\u%04x`, r)
+			}
+			n += size - 1
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquoteValue removes the quotes around v, if v is a quoted value. Double
+// quoted values have their escape sequences interpreted, while single quoted
+// values are taken literally. Values where the closing quote is not the last
+// character, such as `"a" b "c"`, are not quoted values and are returned as
+// is, as are backslashes not followed by a valid escape sequence, such as in
+// "C:\xyz".
+func unquoteValue(v string) string {
+	if !isQuoted(v) {
+		return v
+	}
+	if v[0] == '\'' {
+		return v[1 : len(v)-1]
+	}
+
+	v = v[1 : len(v)-1]
+	if strings.IndexByte(v, '\\') < 0 {
+		return v
+	}
+
+	var b strings.Builder
+	for n := 0; n < len(v); n++ {
+		c := v[n]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		n++
+		switch v[n] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u', 'U':
+			size := 4
+			if v[n] == 'U' {
+				size = 8
+			}
+			r, ok := hexEscape(v[n+1:], size)
+			if !ok || !utf8.ValidRune(rune(r)) {
+				// not an escape sequence, kept as is
+				b.WriteByte('\\')
+				b.WriteByte(v[n])
+				continue
+			}
+			b.WriteRune(rune(r))
+			n += size
+		case 'x':
+			x, ok := hexEscape(v[n+1:], 2)
+			if !ok {
+				b.WriteByte('\\')
+				b.WriteByte(v[n])
+				continue
+			}
+			b.WriteByte(byte(x))
+			n += 2
+		case '\\', '"', '\'':
+			b.WriteByte(v[n])
+		default:
+			// unknown sequence, kept as is
+			b.WriteByte('\\')
+			b.WriteByte(v[n])
+		}
+	}
+	return b.String()
+}
+
+// hexEscape parses the size hexadecimal digits at the start of s
+func hexEscape(s string, size int) (uint64, bool) {
+	if len(s) < size {
+		return 0, false
+	}
+	r, err := strconv.ParseUint(s[:size], 16, 32)
+	return r, err == nil
+}
+
+// isQuoted returns true if v starts with a quote and its matching closing
+// quote is the last character of v. In double quoted values, quotes preceded
+// by a backslash do not close the value.
+func isQuoted(v string) bool {
+	if len(v) < 2 || (v[0] != '"' && v[0] != '\'') {
+		return false
+	}
+	q := v[0]
+	for n := 1; n < len(v); n++ {
+		switch v[n] {
+		case '\\':
+			if q == '"' {
+				n++
+			}
+		case q:
+			return n == len(v)-1
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package ini
+
+import "strings"
+
+// RedactedValue replaces the value of secret keys in the output of Redacted.
+const RedactedValue = "***"
+
+// secretWords are the words that, when found in a key name, cause its value
+// to be considered secret
+var secretWords = []string{"password", "passwd", "secret", "token", "credential", "private", "apikey", "api_key", "api-key"}
+
+// IsSecretKey returns true if the key name suggests its value is a secret,
+// such as a password or an API token.
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, w := range secretWords {
+		if strings.Contains(key, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the ini where the values of keys for which
+// IsSecretKey returns true are replaced with RedactedValue.
+func (i Ini) Redacted() Ini {
+	res := New()
+
+	for n, s := range i {
+		sub := make(map[string]string)
+		for k, v := range s {
+			if IsSecretKey(k) {
+				v = RedactedValue
+			}
+			sub[k] = v
+		}
+		res[n] = sub
+	}
+
+	return res
+}
@@ -0,0 +1,453 @@
+package ini
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// IniSafe is a version of Ini that is safe for concurrent use. Sections can
+// additionally be marked read-only.
+//
+// Data is never modified in place: writers build a modified copy and swap it
+// in, so readers access an immutable snapshot without taking any lock. Only
+// the sections being modified are copied. By default writers are serialized
+// by a single lock, held while copying the modified section. See
+// NewSafeSharded for workloads with many writers to different sections.
+type IniSafe struct {
+	lk       sync.Mutex // serializes writers
+	data     atomic.Pointer[Ini]
+	readOnly map[string]bool
+	subs     map[*subscription]struct{}
+	onChange []func(Change)
+	gen      uint64 // incremented on each change
+	savedGen uint64 // value of gen when last loaded or saved
+
+	historyMax int
+	undo, redo []Ini
+
+	lookup atomic.Pointer[lookup] // settings used by Get, nil by default
+
+	shards *[shardCount]sync.Mutex // per-section locks, nil unless sharded
+}
+
+// shardCount is the number of locks used by a sharded IniSafe
+const shardCount = 64
+
+// NewSafe returns a new empty IniSafe.
+func NewSafe() *IniSafe {
+	res := &IniSafe{}
+	res.store(New())
+	return res
+}
+
+// NewSafeSharded returns a new empty IniSafe using per-section locks for
+// methods modifying a single section, such as Set, Unset or SetMany. Copying
+// and modifying the section is done while holding the lock of that section
+// only, so writers to different sections run in parallel. They still share
+// a lock while publishing the new version, which copies the list of sections
+// but none of their values, and while notifying OnChange callbacks and
+// subscribers. Methods modifying the whole data, such as Load or Update, wait
+// for all the per-section writers.
+func NewSafeSharded() *IniSafe {
+	res := NewSafe()
+	res.shards = new([shardCount]sync.Mutex)
+	return res
+}
+
+// shard returns the lock of a (lowercase) section in sharded mode
+func (i *IniSafe) shard(section string) *sync.Mutex {
+	// FNV-1a
+	h := uint32(2166136261)
+	for n := 0; n < len(section); n++ {
+		h = (h ^ uint32(section[n])) * 16777619
+	}
+	return &i.shards[h%shardCount]
+}
+
+// lockAll acquires the locks needed to modify any section: all the section
+// locks in sharded mode, then i.lk.
+func (i *IniSafe) lockAll() {
+	if i.shards != nil {
+		for n := range i.shards {
+			i.shards[n].Lock()
+		}
+	}
+	i.lk.Lock()
+}
+
+func (i *IniSafe) unlockAll() {
+	i.lk.Unlock()
+	if i.shards != nil {
+		for n := range i.shards {
+			i.shards[n].Unlock()
+		}
+	}
+}
+
+// modify calls fn with an Ini holding a copy of a single section, and
+// publishes the result as the new content of that section, unless fn
+// returns false. It returns ErrReadOnly if the section is read-only.
+func (i *IniSafe) modify(section string, fn func(data Ini) bool) error {
+	section = strings.ToLower(section)
+	if i.shards != nil {
+		// the section can only be modified by holders of its lock, so it
+		// can be copied before taking i.lk
+		lk := i.shard(section)
+		lk.Lock()
+		defer lk.Unlock()
+	} else {
+		i.lk.Lock()
+		defer i.lk.Unlock()
+	}
+	if i.isReadOnly(section) {
+		return ErrReadOnly
+	}
+
+	tmp := make(Ini, 1)
+	if s, ok := i.snapshot()[section]; ok {
+		sub := make(map[string]string, len(s)+1)
+		for k, v := range s {
+			sub[k] = v
+		}
+		tmp[section] = sub
+	}
+	if !fn(tmp) {
+		return nil
+	}
+
+	if i.shards != nil {
+		i.lk.Lock()
+		defer i.lk.Unlock()
+	}
+	if i.readOnly[section] {
+		// made read-only meanwhile
+		return ErrReadOnly
+	}
+	cur := i.snapshot()
+	data := make(Ini, len(cur)+1)
+	for n, s := range cur {
+		data[n] = s
+	}
+	if s, ok := tmp[section]; ok {
+		data[section] = s
+	} else {
+		delete(data, section)
+	}
+	i.commit(data, section)
+	return nil
+}
+
+// snapshot returns the current data, which must not be modified
+func (i *IniSafe) snapshot() Ini {
+	if p := i.data.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func (i *IniSafe) store(data Ini) {
+	i.data.Store(&data)
+}
+
+// commit replaces the current data, records the previous data in the
+// history, notifies subscribers and returns the changes. section is the only
+// (lowercase) section that was modified, or empty if any section may have
+// been. The caller must hold i.lk.
+func (i *IniSafe) commit(data Ini, section string) []Change {
+	old := i.snapshot()
+	changes := i.replace(old, data, section)
+	if len(changes) > 0 {
+		i.record(old)
+	}
+	return changes
+}
+
+// replace stores data, which replaces old, and notifies subscribers of the
+// changes, which are returned. The caller must hold i.lk.
+func (i *IniSafe) replace(old, data Ini, section string) []Change {
+	i.store(data)
+
+	var changes []Change
+	if section == "" {
+		changes = old.Diff(data)
+	} else {
+		changes = diffSection(nil, section, old[section], data[section])
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	i.gen++
+	for _, fn := range i.onChange {
+		for _, c := range changes {
+			fn(c)
+		}
+	}
+	i.notify(changes)
+	return changes
+}
+
+// OnChange registers fn to be called for each value modified by any method,
+// with the value before and after the change. Callbacks run synchronously
+// while the write lock is held, so they must not modify the IniSafe.
+func (i *IniSafe) OnChange(fn func(Change)) {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	i.onChange = append(i.onChange, fn)
+}
+
+// Load will parse source and merge loaded values. Read-only sections are
+// updated too. If parsing fails, no value is changed.
+func (i *IniSafe) Load(source io.Reader) error {
+	_, err := i.ReadFrom(source)
+	return err
+}
+
+// ReadFrom implements io.ReaderFrom. If parsing fails, no value is changed.
+func (i *IniSafe) ReadFrom(r io.Reader) (int64, error) {
+	i.lockAll()
+	defer i.unlockAll()
+
+	data := i.snapshot().clone()
+	n, err := data.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	// values are merged, so unsaved changes remain unsaved
+	clean := i.gen == i.savedGen
+	i.commit(data, "")
+	if clean {
+		i.savedGen = i.gen
+	}
+	return n, nil
+}
+
+// Reload parses r into a new structure which then replaces all the current
+// values in a single step, and returns the changes. Unlike Load, values not
+// present in r are removed. If parsing fails, no value is changed.
+// Subscribers and OnChange callbacks are notified of the changes.
+func (i *IniSafe) Reload(r io.Reader) ([]Change, error) {
+	data := New()
+	if err := data.Load(r); err != nil {
+		return nil, err
+	}
+
+	i.lockAll()
+	defer i.unlockAll()
+
+	changes := i.commit(data, "")
+	i.savedGen = i.gen
+	return changes, nil
+}
+
+// Write generates a ini file and writes it to the provided output. No lock
+// is held while writing, so a slow writer does not block other callers.
+func (i *IniSafe) Write(d io.Writer) error {
+	return i.snapshot().Write(d)
+}
+
+// WriteTo implements io.WriterTo. No lock is held while writing, so a slow
+// writer does not block other callers.
+func (i *IniSafe) WriteTo(w io.Writer) (int64, error) {
+	return i.snapshot().WriteTo(w)
+}
+
+// String implements fmt.Stringer, see Ini.String.
+func (i *IniSafe) String() string {
+	return i.snapshot().String()
+}
+
+// Get returns a value for a given key. Unlike other methods, Get applies
+// the lookup settings such as SetRootFallback.
+func (i *IniSafe) Get(section, key string) (string, bool) {
+	data := i.snapshot()
+	l := i.lookup.Load()
+	if l == nil {
+		return data.Get(section, key)
+	}
+	v, ok := l.get(data, strings.ToLower(section), strings.ToLower(key))
+	if !ok {
+		getMiss(section, key)
+	}
+	return v, ok
+}
+
+// Snapshot returns a copy of the current data. The copy can be freely read,
+// modified or serialized without affecting the IniSafe.
+func (i *IniSafe) Snapshot() Ini {
+	return i.snapshot().clone()
+}
+
+// Sections returns the names of all sections, in no particular order.
+func (i *IniSafe) Sections() []string {
+	return i.snapshot().Sections()
+}
+
+// Keys returns the names of all keys in a section, in no particular order.
+func (i *IniSafe) Keys(section string) []string {
+	return i.snapshot().Keys(section)
+}
+
+// SectionsSorted returns the names of all sections in alphabetical order.
+func (i *IniSafe) SectionsSorted() []string {
+	return i.snapshot().SectionsSorted()
+}
+
+// KeysSorted returns the names of all keys in a section in alphabetical
+// order.
+func (i *IniSafe) KeysSorted(section string) []string {
+	return i.snapshot().KeysSorted(section)
+}
+
+// SectionMap returns a copy of the values of a section, or nil if the section
+// does not exist.
+func (i *IniSafe) SectionMap(section string) map[string]string {
+	return i.snapshot().SectionMap(section)
+}
+
+// Set changes a value. It returns ErrReadOnly if the section is read-only.
+func (i *IniSafe) Set(section, key, value string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.Set(section, key, value)
+		return true
+	})
+}
+
+// SetE changes a value like Set, but first checks that the section and key
+// names can be written and loaded back, see Ini.SetE.
+func (i *IniSafe) SetE(section, key, value string) error {
+	return i.SetEWithOptions(section, key, value, nil)
+}
+
+// SetEWithOptions changes a value like SetE, checking names with the given
+// options, see Ini.SetEWithOptions.
+func (i *IniSafe) SetEWithOptions(section, key, value string, opts *NameOptions) error {
+	if err := CheckNameWithOptions(section, key, opts); err != nil {
+		return err
+	}
+	return i.Set(section, key, value)
+}
+
+// SetMany changes multiple values of a section in a single step, so readers
+// see either none or all of them. It returns ErrReadOnly if the section is
+// read-only.
+func (i *IniSafe) SetMany(section string, kv map[string]string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.SetMany(section, kv)
+		return true
+	})
+}
+
+// SetSection replaces all the values of a section with kv in a single step.
+// It returns ErrReadOnly if the section is read-only.
+func (i *IniSafe) SetSection(section string, kv map[string]string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.SetSection(section, kv)
+		return true
+	})
+}
+
+// Unset removes a value. It returns ErrReadOnly if the section is read-only.
+func (i *IniSafe) Unset(section, key string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.Unset(section, key)
+		return true
+	})
+}
+
+// DeleteSection removes a section and all its values. It returns ErrReadOnly
+// if the section is read-only.
+func (i *IniSafe) DeleteSection(section string) error {
+	return i.modify(section, func(data Ini) bool {
+		data.DeleteSection(section)
+		return true
+	})
+}
+
+// SetIfEquals sets the value of a key to new only if its current value is
+// old, and returns true if the value was changed. A missing key is treated as
+// having an empty value. It returns false for read-only sections.
+func (i *IniSafe) SetIfEquals(section, key, old, new string) bool {
+	changed := false
+	err := i.modify(section, func(data Ini) bool {
+		if v, _ := data.Get(section, key); v != old {
+			return false
+		}
+		data.Set(section, key, new)
+		changed = true
+		return true
+	})
+	return changed && err == nil
+}
+
+// Update calls fn with a copy of the data and, if fn returns nil, replaces
+// the data with it in a single step, so readers see either none or all of
+// the changes made by fn. If fn returns an error or modifies a read-only
+// section, all changes are discarded and the error (or ErrReadOnly) is
+// returned. fn must not keep a reference to the Ini after returning.
+func (i *IniSafe) Update(fn func(Ini) error) error {
+	i.lockAll()
+	defer i.unlockAll()
+
+	cur := i.snapshot()
+	data := cur.clone()
+	if err := fn(data); err != nil {
+		return err
+	}
+	for section := range i.readOnly {
+		if !sameSection(cur[section], data[section]) {
+			return ErrReadOnly
+		}
+	}
+	i.commit(data, "")
+	return nil
+}
+
+func sameSection(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+// SetReadOnly marks a section as read-only (or not), causing calls to Set and
+// Unset on that section to fail.
+func (i *IniSafe) SetReadOnly(section string, readOnly bool) {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	section = strings.ToLower(section)
+	if !readOnly {
+		delete(i.readOnly, section)
+		return
+	}
+	if i.readOnly == nil {
+		i.readOnly = make(map[string]bool)
+	}
+	i.readOnly[section] = true
+}
+
+// isReadOnly returns true if the (lowercase) section is read-only. In
+// sharded mode, i.lk must not be held; otherwise the caller must hold it.
+func (i *IniSafe) isReadOnly(section string) bool {
+	if i.shards != nil {
+		i.lk.Lock()
+		defer i.lk.Unlock()
+	}
+	return i.readOnly[section]
+}
+
+// IsReadOnly returns true if the section is read-only.
+func (i *IniSafe) IsReadOnly(section string) bool {
+	i.lk.Lock()
+	defer i.lk.Unlock()
+
+	return i.readOnly[strings.ToLower(section)]
+}
@@ -0,0 +1,317 @@
+package ini_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestIniSafeReadOnly(t *testing.T) {
+	i := ini.NewSafe()
+	if err := i.Load(strings.NewReader("[core]\nport=80\n[plugin]\nenabled=true\n")); err != nil {
+		t.Fatalf("failed to parse ini: %s", err)
+	}
+	i.SetReadOnly("Core", true)
+
+	if err := i.Set("core", "port", "8080"); err != ini.ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if err := i.Unset("core", "port"); err != ini.ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if err := i.Set("plugin", "enabled", "false"); err != nil {
+		t.Errorf("failed to set plugin value: %s", err)
+	}
+	if v, _ := i.Get("core", "port"); v != "80" {
+		t.Errorf("read-only value was modified: %#v", v)
+	}
+
+	i.SetReadOnly("core", false)
+	if err := i.Set("core", "port", "8080"); err != nil {
+		t.Errorf("failed to set value after removing read-only flag: %s", err)
+	}
+}
+
+func TestIniSafeConcurrent(t *testing.T) {
+	i := ini.NewSafe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for n := 0; n < 1000; n++ {
+			i.Set("section", "key", "value")
+			i.Unset("section", "key")
+		}
+	}()
+
+	for n := 0; n < 1000; n++ {
+		i.Get("section", "key")
+		i.Write(io.Discard)
+	}
+	<-done
+}
+
+func TestIniSafeUpdate(t *testing.T) {
+	i := ini.NewSafe()
+	i.Set("db", "host", "a")
+	i.Set("core", "port", "80")
+	i.SetReadOnly("core", true)
+
+	err := i.Update(func(data ini.Ini) error {
+		data.Set("db", "host", "b")
+		data.Set("db", "port", "5432")
+		return errors.New("abort")
+	})
+	if err == nil || err.Error() != "abort" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if v, _ := i.Get("db", "host"); v != "a" {
+		t.Errorf("changes were not rolled back, db/host=%#v", v)
+	}
+
+	err = i.Update(func(data ini.Ini) error {
+		data.Set("db", "host", "b")
+		data.Set("core", "port", "8080")
+		return nil
+	})
+	if err != ini.ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+
+	err = i.Update(func(data ini.Ini) error {
+		data.Set("db", "host", "b")
+		data.Set("db", "port", "5432")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to update: %s", err)
+	}
+	if v, _ := i.Get("db", "port"); v != "5432" {
+		t.Errorf("update was not applied, db/port=%#v", v)
+	}
+}
+
+func TestIniSafeSetIfEquals(t *testing.T) {
+	i := ini.NewSafe()
+
+	if !i.SetIfEquals("lock", "owner", "", "a") {
+		t.Errorf("failed to set missing key")
+	}
+	if i.SetIfEquals("lock", "owner", "", "b") {
+		t.Errorf("value was changed while old value didn't match")
+	}
+	if !i.SetIfEquals("lock", "owner", "a", "b") {
+		t.Errorf("failed to change matching value")
+	}
+	if v, _ := i.Get("lock", "owner"); v != "b" {
+		t.Errorf("unexpected value %#v", v)
+	}
+}
+
+func TestIniSafeSnapshot(t *testing.T) {
+	i := ini.NewSafe()
+	i.Set("section", "key", "a")
+
+	snap := i.Snapshot()
+	snap.Set("section", "key", "b")
+	i.Set("section", "other", "c")
+
+	if v, _ := i.Get("section", "key"); v != "a" {
+		t.Errorf("modifying the snapshot changed the original, got %#v", v)
+	}
+	if _, ok := snap.Get("section", "other"); ok {
+		t.Errorf("snapshot was modified by later changes")
+	}
+}
+
+// blockingWriter blocks on its first write until release is closed
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	select {
+	case <-w.started:
+	default:
+		close(w.started)
+		<-w.release
+	}
+	return len(p), nil
+}
+
+func TestIniSafeWriteToNonBlocking(t *testing.T) {
+	i := ini.NewSafe()
+	i.Set("section", "key", "a")
+
+	w := &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		i.WriteTo(w)
+	}()
+
+	<-w.started
+	// these would deadlock if WriteTo held a lock during I/O
+	i.Set("section", "key", "b")
+	i.Get("section", "key")
+	close(w.release)
+	<-done
+}
+
+func TestIniSafeOnChange(t *testing.T) {
+	i := ini.NewSafe()
+	var changes []string
+	i.OnChange(func(c ini.Change) { changes = append(changes, c.String()) })
+
+	i.Set("db", "host", "a")
+	i.Set("db", "port", "5432")
+	i.Set("db", "host", "a") // no change
+	i.Unset("db", "port")
+	i.DeleteSection("db")
+
+	expect := []string{
+		"+ db.host=a",
+		"+ db.port=5432",
+		"- db.port=5432",
+		"- db.host=a",
+	}
+	if strings.Join(changes, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected changes:\n%s", strings.Join(changes, "\n"))
+	}
+}
+
+func TestIniSafeReload(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("[db]\nhost=a\nport=5432\n"))
+
+	changes, err := i.Reload(strings.NewReader("[db]\nhost=b\n[cache]\nsize=10\n"))
+	if err != nil {
+		t.Fatalf("failed to reload: %s", err)
+	}
+
+	var res []string
+	for _, c := range changes {
+		res = append(res, c.String())
+	}
+	expect := []string{
+		"+ cache.size=10",
+		"~ db.host=a -> b",
+		"- db.port=5432",
+	}
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected changes:\n%s", strings.Join(res, "\n"))
+	}
+
+	if _, err := i.Reload(strings.NewReader("invalid")); err == nil {
+		t.Errorf("expected error when reloading invalid data")
+	}
+	if v, _ := i.Get("db", "host"); v != "b" {
+		t.Errorf("failed reload modified values, db/host=%#v", v)
+	}
+}
+
+func TestIniSafeSaveReload(t *testing.T) {
+	i := ini.NewSafe()
+	i.Load(strings.NewReader("path=\"C:\\temp\"\nname='x'\n[s]\nq=\"a\" b\n"))
+
+	path := filepath.Join(t.TempDir(), "t.ini")
+	if err := i.SaveFile(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// saved values are loaded back as they were
+	changes, err := i.Reload(f)
+	if err != nil {
+		t.Fatalf("failed to reload: %s", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("values changed after a save and reload: %v", changes)
+	}
+	if v, _ := i.Get("root", "path"); v != `"C:\temp"` {
+		t.Errorf("unexpected path %q", v)
+	}
+}
+
+func TestIniSafeSetMany(t *testing.T) {
+	i := ini.NewSafe()
+	gen := 0
+	i.OnChange(func(c ini.Change) { gen++ })
+
+	if err := i.SetMany("DB", map[string]string{"host": "localhost", "port": "5432"}); err != nil {
+		t.Fatalf("failed to set: %s", err)
+	}
+	if v, _ := i.Get("db", "port"); v != "5432" {
+		t.Errorf("unexpected value %q", v)
+	}
+	if gen != 2 {
+		t.Errorf("expected 2 changes, got %d", gen)
+	}
+
+	i.SetReadOnly("db", true)
+	if err := i.SetMany("db", map[string]string{"host": "other"}); !errors.Is(err, ini.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestSafeSharded(t *testing.T) {
+	i := ini.NewSafeSharded()
+	i.EnableHistory(1000)
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			section := fmt.Sprintf("s%d", n)
+			for k := 0; k < 100; k++ {
+				if err := i.Set(section, fmt.Sprintf("k%d", k), "v"); err != nil {
+					t.Errorf("failed to set: %s", err)
+				}
+			}
+		}(n)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < 10; n++ {
+			i.Update(func(data ini.Ini) error {
+				data.Set("other", "n", fmt.Sprint(n))
+				return nil
+			})
+		}
+	}()
+	wg.Wait()
+
+	for n := 0; n < 8; n++ {
+		if k := i.Keys(fmt.Sprintf("s%d", n)); len(k) != 100 {
+			t.Errorf("expected 100 keys in section s%d, got %d", n, len(k))
+		}
+	}
+	if v, _ := i.Get("other", "n"); v != "9" {
+		t.Errorf("unexpected value %q", v)
+	}
+
+	i.SetReadOnly("s0", true)
+	if err := i.Set("s0", "x", "y"); err != ini.ErrReadOnly {
+		t.Errorf("unexpected error %v for a read-only section", err)
+	}
+	if !i.SetIfEquals("s1", "k1", "v", "w") || i.SetIfEquals("s1", "k1", "v", "w") {
+		t.Errorf("unexpected SetIfEquals result")
+	}
+	if err := i.DeleteSection("s2"); err != nil || len(i.Keys("s2")) != 0 {
+		t.Errorf("failed to delete section: %v", err)
+	}
+}
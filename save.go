@@ -0,0 +1,57 @@
+package ini
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SaveFile writes the ini to the file at path. The data is first written to
+// a temporary file in the same directory which then replaces path, so readers
+// never see a partially written file. The permissions of an existing file are
+// kept, new files are created with mode 0644.
+func (i Ini) SaveFile(path string) error {
+	return i.SaveFileWithOptions(path, nil)
+}
+
+// SaveFileWithOptions writes the ini to the file at path like SaveFile, using
+// the given options. opts can be nil.
+func (i Ini) SaveFileWithOptions(path string, opts *WriteOptions) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return i.WriteWithOptions(w, opts)
+	})
+}
+
+// writeFileAtomic replaces the file at path with the data written by fn, see
+// SaveFile
+func writeFileAtomic(path string, fn func(w io.Writer) error) error {
+	mode := os.FileMode(0644)
+	if st, err := os.Stat(path); err == nil {
+		mode = st.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	err = fn(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package ini_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ini")
+
+	i := ini.New()
+	i.Set("root", "var1", "value1")
+	i.Set("b", "var3", "value3")
+	i.Set("a", "var2", "value2")
+
+	if err := i.SaveFile(path); err != nil {
+		t.Fatalf("failed to save file: %s", err)
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %s", err)
+	}
+	if string(buf) != "var1=value1\n\n[a]\nvar2=value2\n\n[b]\nvar3=value3\n\n" {
+		t.Errorf("unexpected file contents %q", buf)
+	}
+
+	res := ini.New()
+	if err := res.LoadFile(path, nil); err != nil {
+		t.Fatalf("failed to load file: %s", err)
+	}
+	if v, ok := res.Get("b", "var3"); !ok || v != "value3" {
+		t.Errorf("failed to get value b/var3, read %#v %#v", v, ok)
+	}
+}
@@ -0,0 +1,200 @@
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema describes the sections and keys allowed in an ini file, indexed by
+// section then key.
+type Schema map[string]map[string]*Rule
+
+// Rule describes the values allowed for a given key.
+type Rule struct {
+	Type     string   // one of string, int, float, bool, duration or enum
+	Required bool     // key must be present
+	Enum     []string // allowed values for type enum
+	Min, Max *float64 // bounds for int, float and duration (in seconds)
+}
+
+// ValidationError describes a value not matching a Schema.
+type ValidationError struct {
+	Section string
+	Key     string // empty for errors about a whole section
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("section %s: %s", e.Section, e.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", e.Section, e.Key, e.Message)
+}
+
+// ParseSchema reads a Schema from an ini where each value describes the
+// rule for the matching key, as a comma separated list starting with the
+// type:
+//
+//	[server]
+//	port = int,required,min=1,max=65535
+//	mode = enum:dev|prod
+//	timeout = duration,max=60
+func ParseSchema(i Ini) (Schema, error) {
+	res := make(Schema)
+
+	for n, s := range i {
+		sub := make(map[string]*Rule)
+		for k, v := range s {
+			r, err := parseRule(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule for %s.%s: %w", n, k, err)
+			}
+			sub[k] = r
+		}
+		res[n] = sub
+	}
+
+	return res, nil
+}
+
+func parseRule(spec string) (*Rule, error) {
+	parts := strings.Split(spec, ",")
+	r := &Rule{Type: strings.TrimSpace(parts[0])}
+
+	if v, ok := strings.CutPrefix(r.Type, "enum:"); ok {
+		r.Type = "enum"
+		r.Enum = strings.Split(v, "|")
+	}
+	switch r.Type {
+	case "string", "int", "float", "bool", "duration", "enum":
+	case "":
+		r.Type = "string"
+	default:
+		return nil, fmt.Errorf("unknown type %s", r.Type)
+	}
+
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		name, val, _ := strings.Cut(p, "=")
+		switch name {
+		case "required":
+			r.Required = true
+		case "min", "max":
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			if name == "min" {
+				r.Min = &f
+			} else {
+				r.Max = &f
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s", p)
+		}
+	}
+
+	return r, nil
+}
+
+// Validate checks i against the schema and returns all the problems found,
+// sorted by section and key. Sections and keys not present in the schema are
+// reported as errors.
+func (s Schema) Validate(i Ini) []*ValidationError {
+	var res []*ValidationError
+
+	names := make(map[string]bool)
+	for n := range s {
+		names[n] = true
+	}
+	for n := range i {
+		names[n] = true
+	}
+
+	for _, n := range sortedKeys(names) {
+		rules, ok := s[n]
+		if !ok {
+			res = append(res, &ValidationError{Section: n, Message: "unknown section"})
+			continue
+		}
+		values := i[n]
+
+		keys := make(map[string]bool)
+		for k := range rules {
+			keys[k] = true
+		}
+		for k := range values {
+			keys[k] = true
+		}
+
+		for _, k := range sortedKeys(keys) {
+			r, ok := rules[k]
+			if !ok {
+				res = append(res, &ValidationError{Section: n, Key: k, Message: "unknown key"})
+				continue
+			}
+			v, ok := values[k]
+			if !ok {
+				if r.Required {
+					res = append(res, &ValidationError{Section: n, Key: k, Message: "missing required key"})
+				}
+				continue
+			}
+			if err := r.Check(v); err != nil {
+				res = append(res, &ValidationError{Section: n, Key: k, Message: err.Error()})
+			}
+		}
+	}
+
+	return res
+}
+
+// Check returns an error if v doesn't match the rule.
+func (r *Rule) Check(v string) error {
+	var num float64
+
+	switch r.Type {
+	case "int":
+		n, err := strconv.ParseInt(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		num = float64(n)
+	case "float":
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", v)
+		}
+		num = f
+	case "bool":
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid boolean %q", v)
+		}
+		return nil
+	case "duration":
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		num = d.Seconds()
+	case "enum":
+		for _, e := range r.Enum {
+			if v == e {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q, must be one of: %s", v, strings.Join(r.Enum, ", "))
+	default:
+		return nil
+	}
+
+	if r.Min != nil && num < *r.Min {
+		return fmt.Errorf("value %s is lower than minimum %g", v, *r.Min)
+	}
+	if r.Max != nil && num > *r.Max {
+		return fmt.Errorf("value %s is greater than maximum %g", v, *r.Max)
+	}
+	return nil
+}
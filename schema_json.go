@@ -0,0 +1,101 @@
+package ini
+
+import "encoding/json"
+
+// patterns matching the string form of values accepted by Rule.Check
+const (
+	intPattern      = `^[+-]?(0[xX][0-9a-fA-F]+|0[bB][01]+|0[oO]?[0-7]+|[0-9]+)$`
+	floatPattern    = `^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`
+	durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+)
+
+// boolValues lists the values accepted by strconv.ParseBool
+var boolValues = []string{"1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False"}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing the
+// json form of ini files matching the schema: an object of sections, each an
+// object of keys, as written by ToJSON. Values are strings matching the
+// syntax of their type, and int, float and bool values can also be json
+// numbers and booleans, as accepted by FromJSON. Keys of the root section
+// can also appear at the top level of the document.
+//
+// Min and Max are only checked for json numbers, as JSON Schema cannot
+// compare values stored as strings. For durations, they are omitted. As with
+// Validate, unknown sections and keys are not allowed.
+func (s Schema) JSONSchema() ([]byte, error) {
+	props := make(map[string]any)
+	var required []string
+
+	for _, n := range sortedKeys(s) {
+		keys := make(map[string]any)
+		var req []string
+		for _, k := range sortedKeys(s[n]) {
+			r := s[n][k]
+			keys[k] = r.jsonSchema()
+			if r.Required {
+				req = append(req, k)
+			}
+		}
+		sub := map[string]any{
+			"type":                 "object",
+			"properties":           keys,
+			"additionalProperties": false,
+		}
+		if req != nil {
+			sub["required"] = req
+			if n != "root" {
+				// root values can be stored at the top level instead
+				required = append(required, n)
+			}
+		}
+		props[n] = sub
+	}
+
+	// keys of the root section stored at the top level
+	for k, r := range s["root"] {
+		if _, ok := props[k]; !ok {
+			props[k] = r.jsonSchema()
+		}
+	}
+
+	doc := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if required != nil {
+		doc["required"] = required
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// jsonSchema returns the JSON Schema of values matching the rule
+func (r *Rule) jsonSchema() map[string]any {
+	switch r.Type {
+	case "int", "float":
+		str := map[string]any{"type": "string", "pattern": intPattern}
+		num := map[string]any{"type": "integer"}
+		if r.Type == "float" {
+			str["pattern"] = floatPattern
+			num["type"] = "number"
+		}
+		if r.Min != nil {
+			num["minimum"] = *r.Min
+		}
+		if r.Max != nil {
+			num["maximum"] = *r.Max
+		}
+		return map[string]any{"anyOf": []any{str, num}}
+	case "bool":
+		return map[string]any{"anyOf": []any{
+			map[string]any{"type": "string", "enum": boolValues},
+			map[string]any{"type": "boolean"},
+		}}
+	case "duration":
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	case "enum":
+		return map[string]any{"type": "string", "enum": r.Enum}
+	}
+	return map[string]any{"type": "string"}
+}
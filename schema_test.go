@@ -0,0 +1,109 @@
+package ini_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestSchema(t *testing.T) {
+	def := ini.New()
+	def.Load(strings.NewReader(`
+[server]
+port = int,required,min=1,max=65535
+mode = enum:dev|prod
+timeout = duration,max=60
+name = string,required
+`))
+	schema, err := ini.ParseSchema(def)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %s", err)
+	}
+
+	i := ini.New()
+	i.Load(strings.NewReader(`
+[server]
+port = 70000
+mode = test
+timeout = 5s
+protr = 8080
+
+[other]
+a = b
+`))
+
+	var res []string
+	for _, e := range schema.Validate(i) {
+		res = append(res, e.Error())
+	}
+	expect := []string{
+		"section other: unknown section",
+		`server.mode: invalid value "test", must be one of: dev, prod`,
+		"server.name: missing required key",
+		"server.port: value 70000 is greater than maximum 65535",
+		"server.protr: unknown key",
+	}
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected validation errors:\n%s", strings.Join(res, "\n"))
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	schema, err := ini.ParseSchema(ini.MustParse("level = int\n[server]\nport = int,required,min=1,max=65535\nmode = enum:dev|prod\ntimeout = duration\n[log]\ndebug = bool\n"))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %s", err)
+	}
+	data, err := schema.JSONSchema()
+	if err != nil {
+		t.Fatalf("failed to export schema: %s", err)
+	}
+
+	type prop map[string]any
+	var doc struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Required             []string        `json:"required"`
+			AdditionalProperties bool            `json:"additionalProperties"`
+			Properties           map[string]prop `json:"properties"`
+			AnyOf                []prop          `json:"anyOf"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to parse exported schema: %s", err)
+	}
+
+	if !reflect.DeepEqual(doc.Required, []string{"server"}) {
+		t.Errorf("unexpected required sections %v", doc.Required)
+	}
+	if level := doc.Properties["level"]; len(level.AnyOf) != 2 {
+		t.Errorf("root key missing at the top level: %+v", level)
+	}
+	server := doc.Properties["server"]
+	if !reflect.DeepEqual(server.Required, []string{"port"}) || server.AdditionalProperties {
+		t.Errorf("unexpected server section %+v", server)
+	}
+
+	// ToJSON writes values as strings, which must match the patterns
+	port := server.Properties["port"]["anyOf"].([]any)
+	str, num := port[0].(map[string]any), port[1].(map[string]any)
+	if str["type"] != "string" || !regexp.MustCompile(str["pattern"].(string)).MatchString("8080") {
+		t.Errorf("unexpected port string schema %v", str)
+	}
+	if num["type"] != "integer" || num["minimum"] != 1.0 || num["maximum"] != 65535.0 {
+		t.Errorf("unexpected port number schema %v", num)
+	}
+	if mode := server.Properties["mode"]; mode["type"] != "string" || len(mode["enum"].([]any)) != 2 {
+		t.Errorf("unexpected mode schema %v", mode)
+	}
+	timeout := server.Properties["timeout"]
+	if re := regexp.MustCompile(timeout["pattern"].(string)); !re.MatchString("1m30s") || re.MatchString("soon") {
+		t.Errorf("unexpected timeout schema %v", timeout)
+	}
+	if debug := doc.Properties["log"].Properties["debug"]; len(debug["anyOf"].([]any)) != 2 {
+		t.Errorf("unexpected debug schema %v", debug)
+	}
+}
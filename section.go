@@ -0,0 +1,133 @@
+package ini
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Section is a handle to a section of an Ini. Changes made through it are
+// made to the Ini.
+type Section struct {
+	Name string // lowercase name, such as "peer[1]" for a repeated section
+	ini  Ini
+}
+
+// Get returns a value of the section.
+func (s Section) Get(key string) (string, bool) {
+	return s.ini.Get(s.Name, key)
+}
+
+// Set changes a value of the section.
+func (s Section) Set(key, value string) {
+	s.ini.Set(s.Name, key, value)
+}
+
+// Keys returns the names of all keys of the section, in alphabetical order.
+func (s Section) Keys() []string {
+	return s.ini.KeysSorted(s.Name)
+}
+
+// Map returns a copy of the values of the section.
+func (s Section) Map() map[string]string {
+	return s.ini.SectionMap(s.Name)
+}
+
+// SectionsAll returns the instances of a section that can be repeated, see
+// LoadOptions.RepeatedSections: the section itself if it exists, followed by
+// its instances "name[0]", "name[1]" and so on, in order.
+func (i Ini) SectionsAll(name string) []Section {
+	name = strings.ToLower(name)
+
+	var res []Section
+	if _, ok := i[name]; ok {
+		res = append(res, Section{Name: name, ini: i})
+	}
+
+	type instance struct {
+		n    int
+		name string
+	}
+	var instances []instance
+	for n := range i {
+		if idx, ok := indexOf(n, name); ok {
+			instances = append(instances, instance{idx, n})
+		}
+	}
+	sort.Slice(instances, func(a, b int) bool { return instances[a].n < instances[b].n })
+	for _, inst := range instances {
+		res = append(res, Section{Name: inst.name, ini: i})
+	}
+	return res
+}
+
+// nextInstance returns the name of the next instance of a repeated section
+func (i Ini) nextInstance(name string) string {
+	next := 0
+	for n := range i {
+		if idx, ok := indexOf(n, name); ok && idx >= next {
+			next = idx + 1
+		}
+	}
+	return name + "[" + strconv.Itoa(next) + "]"
+}
+
+// repeatedBase returns the name of the repeated section n is an instance of,
+// if any
+func repeatedBase(n string, repeated []string) (string, int, bool) {
+	for _, r := range repeated {
+		if idx, ok := indexOf(n, strings.ToLower(r)); ok {
+			return strings.ToLower(r), idx, true
+		}
+	}
+	return n, 0, false
+}
+
+// isRepeated returns true if section is one of the repeated sections
+func isRepeated(section string, repeated []string) bool {
+	for _, r := range repeated {
+		if strings.EqualFold(section, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumerateSections returns the sections named prefix followed by a number,
+// such as "server1", "server2" or "server.1", "server.2", in numeric order.
+func (i Ini) EnumerateSections(prefix string) []Section {
+	prefix = strings.ToLower(prefix)
+
+	type numbered struct {
+		n    int
+		name string
+	}
+	var found []numbered
+	for name := range i {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		rest = strings.TrimPrefix(rest, ".")
+		if rest == "" || strings.TrimLeft(rest, "0123456789") != "" {
+			continue
+		}
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			continue
+		}
+		found = append(found, numbered{n, name})
+	}
+	sort.Slice(found, func(a, b int) bool {
+		if found[a].n != found[b].n {
+			return found[a].n < found[b].n
+		}
+		return found[a].name < found[b].name
+	})
+
+	res := make([]Section, len(found))
+	for n, f := range found {
+		res[n] = Section{Name: f.name, ini: i}
+	}
+	return res
+}
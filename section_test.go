@@ -0,0 +1,89 @@
+package ini_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestRepeatedSections(t *testing.T) {
+	src := "[Interface]\nprivatekey=abc\n\n" +
+		"[Peer]\npublickey=p1\nendpoint=a:51820\n\n" +
+		"[Peer]\npublickey=p2\n\n" +
+		"[[peer]]\npublickey=p3\n"
+
+	i := ini.New()
+	if err := i.LoadWithOptions(strings.NewReader(src), &ini.LoadOptions{RepeatedSections: []string{"Peer"}}); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+
+	peers := i.SectionsAll("peer")
+	if len(peers) != 3 {
+		t.Fatalf("expected 3 peers, got %d", len(peers))
+	}
+	for n, expect := range []string{"p1", "p2", "p3"} {
+		if v, _ := peers[n].Get("PublicKey"); v != expect {
+			t.Errorf("unexpected key %q for peer %d", v, n)
+		}
+	}
+	peers[1].Set("endpoint", "b:51820")
+	if v, _ := i.Get("peer[1]", "endpoint"); v != "b:51820" {
+		t.Errorf("value not set through the handle")
+	}
+
+	// instance 10 must be written after instance 2
+	i.Set("peer[10]", "publickey", "p11")
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{RepeatedSections: []string{"peer"}}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	expect := "[interface]\nprivatekey=abc\n\n" +
+		"[peer]\nendpoint=a:51820\npublickey=p1\n\n" +
+		"[peer]\nendpoint=b:51820\npublickey=p2\n\n" +
+		"[peer]\npublickey=p3\n\n" +
+		"[peer]\npublickey=p11\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	// loading the output back would renumber peer[10] as peer[3]
+	strict := &ini.WriteOptions{RepeatedSections: []string{"peer"}, Strict: true}
+	if err := i.WriteWithOptions(&bytes.Buffer{}, strict); err == nil {
+		t.Errorf("expected an error for instances with a gap")
+	}
+	i.DeleteSection("peer[10]")
+	i.Set("peer[3]", "publickey", "p11")
+	if err := i.WriteWithOptions(&bytes.Buffer{}, strict); err != nil {
+		t.Errorf("failed to write: %s", err)
+	}
+
+	// a plain [peer] section would be loaded back as an instance
+	i.Set("peer", "publickey", "p0")
+	if err := i.WriteWithOptions(&bytes.Buffer{}, strict); err == nil {
+		t.Errorf("expected an error for a section stored outside of instances")
+	}
+}
+
+func TestEnumerateSections(t *testing.T) {
+	i := ini.MustParse("[server10]\na=1\n[server2]\na=1\n[server1]\na=1\n[servers]\na=1\n[server2x]\na=1\n[other1]\na=1\n")
+
+	var names []string
+	for _, s := range i.EnumerateSections("Server") {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "server1,server2,server10" {
+		t.Errorf("unexpected sections %v", names)
+	}
+
+	i = ini.MustParse("[db.2]\na=1\n[db.1]\nhost=x\n")
+	res := i.EnumerateSections("db")
+	if len(res) != 2 || res[0].Name != "db.1" {
+		t.Fatalf("unexpected sections %v", res)
+	}
+	if v, _ := res[0].Get("host"); v != "x" {
+		t.Errorf("unexpected value %q", v)
+	}
+}
@@ -0,0 +1,52 @@
+package ini
+
+// Stats holds statistics about the contents of an Ini.
+type Stats struct {
+	Sections   int // number of sections
+	Keys       int // number of keys in all sections
+	ValueBytes int // total length of all values
+
+	LongestValue   int    // length of the longest value
+	LongestSection string // section of the longest value
+	LongestKey     string // key of the longest value
+
+	// DuplicateValues is the number of non-empty values that are identical to
+	// a value stored elsewhere, and DuplicateBytes their total length. These
+	// hint at settings that could be factored out.
+	DuplicateValues int
+	DuplicateBytes  int
+}
+
+// Stats returns statistics about the contents of i.
+func (i Ini) Stats() Stats {
+	var res Stats
+	seen := make(map[string]bool)
+
+	for _, n := range sortedKeys(i) {
+		s := i[n]
+		res.Sections++
+		for _, k := range sortedKeys(s) {
+			v := s[k]
+			res.Keys++
+			res.ValueBytes += len(v)
+			if len(v) > res.LongestValue {
+				res.LongestValue, res.LongestSection, res.LongestKey = len(v), n, k
+			}
+			if v == "" {
+				continue
+			}
+			if seen[v] {
+				res.DuplicateValues++
+				res.DuplicateBytes += len(v)
+			}
+			seen[v] = true
+		}
+	}
+
+	return res
+}
+
+// Stats returns statistics about the current values, see Ini.Stats.
+func (i *IniSafe) Stats() Stats {
+	return i.snapshot().Stats()
+}
@@ -0,0 +1,25 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestStats(t *testing.T) {
+	i := ini.MustParse("a=12345\n[s1]\nhost=db.local\n[s2]\nhost=db.local\nport=\n")
+
+	expect := ini.Stats{
+		Sections:        3,
+		Keys:            4,
+		ValueBytes:      21,
+		LongestValue:    8,
+		LongestSection:  "s1",
+		LongestKey:      "host",
+		DuplicateValues: 1,
+		DuplicateBytes:  8,
+	}
+	if st := i.Stats(); st != expect {
+		t.Errorf("unexpected stats %+v", st)
+	}
+}
@@ -0,0 +1,122 @@
+package ini
+
+import (
+	"strings"
+	"sync"
+)
+
+// maxPending is the number of changes queued for a subscriber before the
+// oldest are dropped
+const maxPending = 1024
+
+type subscription struct {
+	section, key string // filters, empty to match anything
+
+	ch     chan Change
+	signal chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	lk    sync.Mutex
+	queue []Change
+}
+
+// Subscribe returns a channel receiving a Change each time a matching value
+// is modified, and a function to cancel the subscription. An empty section
+// matches all sections, and an empty key matches all keys of the section.
+//
+// Changes are queued so writers never wait for subscribers, and are delivered
+// in order. If a subscriber falls more than 1024 changes behind, the oldest
+// pending changes are dropped and replaced by a single Change of kind
+// Overflow, after which the subscriber should read the current values again.
+//
+// cancel must be called once the subscription is no longer needed, as it
+// stops the goroutine delivering changes; the channel is then closed.
+func (i *IniSafe) Subscribe(section, key string) (<-chan Change, func()) {
+	s := &subscription{
+		section: strings.ToLower(section),
+		key:     strings.ToLower(key),
+		ch:      make(chan Change),
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	i.lk.Lock()
+	if i.subs == nil {
+		i.subs = make(map[*subscription]struct{})
+	}
+	i.subs[s] = struct{}{}
+	i.lk.Unlock()
+
+	go s.run()
+
+	cancel := func() {
+		i.lk.Lock()
+		delete(i.subs, s)
+		i.lk.Unlock()
+		s.once.Do(func() { close(s.done) })
+	}
+	return s.ch, cancel
+}
+
+// notify queues changes for matching subscribers. The caller must hold i.lk.
+func (i *IniSafe) notify(changes []Change) {
+	for s := range i.subs {
+		s.push(changes)
+	}
+}
+
+func (s *subscription) push(changes []Change) {
+	s.lk.Lock()
+	for _, c := range changes {
+		if (s.section == "" || s.section == c.Section) && (s.key == "" || s.key == c.Key) {
+			s.queue = append(s.queue, c)
+		}
+	}
+	if len(s.queue) > maxPending {
+		// keep an Overflow marker followed by the most recent changes
+		drop := len(s.queue) - maxPending + 1
+		if s.queue[0].Kind == Overflow {
+			drop++
+		}
+		s.queue = append([]Change{{Kind: Overflow}}, s.queue[drop:]...)
+	}
+	s.lk.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) run() {
+	defer close(s.ch)
+
+	for {
+		select {
+		case <-s.signal:
+		case <-s.done:
+			return
+		}
+
+		// take changes one at a time so push can still drop the oldest ones
+		// while the subscriber is slow
+		for {
+			s.lk.Lock()
+			if len(s.queue) == 0 {
+				s.queue = nil
+				s.lk.Unlock()
+				break
+			}
+			c := s.queue[0]
+			s.queue = s.queue[1:]
+			s.lk.Unlock()
+
+			select {
+			case s.ch <- c:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ini_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestSubscribe(t *testing.T) {
+	i := ini.NewSafe()
+	ch, cancel := i.Subscribe("db", "host")
+
+	i.Set("db", "host", "a")
+	i.Set("db", "port", "5432")
+	i.Set("db", "Host", "b")
+	i.Unset("db", "host")
+
+	expect := []ini.Change{
+		{Kind: ini.Added, Section: "db", Key: "host", New: "a"},
+		{Kind: ini.Modified, Section: "db", Key: "host", Old: "a", New: "b"},
+		{Kind: ini.Removed, Section: "db", Key: "host", Old: "b"},
+	}
+	for _, want := range expect {
+		select {
+		case c := <-ch:
+			if c != want {
+				t.Errorf("unexpected change %v, expected %v", c, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for change %v", want)
+		}
+	}
+
+	cancel()
+	for range ch {
+		// drain until closed
+	}
+}
+
+func TestSubscribeOverflow(t *testing.T) {
+	i := ini.NewSafe()
+	ch, cancel := i.Subscribe("", "")
+	defer cancel()
+
+	// nobody reads until all changes are made
+	for n := 0; n < 3000; n++ {
+		i.Set("s", "k", fmt.Sprint(n))
+	}
+
+	var res []ini.Change
+	timeout := time.After(time.Second)
+	for len(res) == 0 || res[len(res)-1].New != "2999" {
+		select {
+		case c := <-ch:
+			res = append(res, c)
+		case <-timeout:
+			t.Fatalf("timeout after %d changes", len(res))
+		}
+	}
+	if len(res) >= 3000 {
+		t.Errorf("expected changes to be dropped, got %d", len(res))
+	}
+	overflow := 0
+	for _, c := range res {
+		if c.Kind == ini.Overflow {
+			overflow++
+		}
+	}
+	if overflow == 0 {
+		t.Errorf("expected an overflow")
+	}
+}
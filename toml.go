@@ -0,0 +1,94 @@
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ToTOML returns a toml document where each section is a table. Dotted
+// section names such as "server.http" become nested tables, and entries of
+// the root section are stored at the top level of the document.
+func (i Ini) ToTOML() ([]byte, error) {
+	doc := make(map[string]any)
+
+	for n, s := range i {
+		tbl := doc
+		if n != "root" {
+			for _, sub := range strings.Split(n, ".") {
+				next, ok := tbl[sub]
+				if !ok {
+					next = make(map[string]any)
+					tbl[sub] = next
+				}
+				nextTbl, ok := next.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("failed to export toml: section %s conflicts with an existing key", n)
+				}
+				tbl = nextTbl
+			}
+		}
+
+		for k, v := range s {
+			if _, ok := tbl[k]; ok {
+				return nil, fmt.Errorf("failed to export toml: key %s.%s conflicts with an existing table", n, k)
+			}
+			tbl[k] = v
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FromTOML parses a toml document and returns the matching Ini. Nested tables
+// are flattened into dotted section names, and top-level values are stored in
+// the root section.
+func FromTOML(data []byte) (Ini, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	res := New()
+	if err := res.loadTOMLTable("root", "", doc); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (i Ini) loadTOMLTable(section, prefix string, tbl map[string]any) error {
+	for k, v := range tbl {
+		if sub, ok := v.(map[string]any); ok {
+			if err := i.loadTOMLTable(prefix+k, prefix+k+".", sub); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case float64:
+			s = strconv.FormatFloat(v, 'g', -1, 64)
+		case bool:
+			s = strconv.FormatBool(v)
+		case time.Time:
+			s = v.Format(time.RFC3339Nano)
+		default:
+			return fmt.Errorf("failed to parse toml: unsupported value type %T for %s.%s", v, section, k)
+		}
+		i.Set(section, k, s)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestTOML(t *testing.T) {
+	src := ini.New()
+	src.Set("root", "var1", "value1")
+	src.Set("server", "name", "main")
+	src.Set("server.http", "port", "8080")
+
+	buf, err := src.ToTOML()
+	if err != nil {
+		t.Fatalf("failed to export toml: %s", err)
+	}
+
+	res, err := ini.FromTOML(buf)
+	if err != nil {
+		t.Fatalf("failed to parse toml: %s", err)
+	}
+
+	if v, ok := res.Get("server.http", "port"); !ok || v != "8080" {
+		t.Errorf("failed to get value server.http/port, read %#v %#v", v, ok)
+	}
+	if v, ok := res.Get("root", "var1"); !ok || v != "value1" {
+		t.Errorf("failed to get value root/var1, read %#v %#v", v, ok)
+	}
+
+	res, err = ini.FromTOML([]byte("enabled = true\n[a.b]\nport = 80\n"))
+	if err != nil {
+		t.Fatalf("failed to parse toml: %s", err)
+	}
+	if v, ok := res.Get("a.b", "port"); !ok || v != "80" {
+		t.Errorf("failed to get value a.b/port, read %#v %#v", v, ok)
+	}
+	if v, ok := res.Get("root", "enabled"); !ok || v != "true" {
+		t.Errorf("failed to get value root/enabled, read %#v %#v", v, ok)
+	}
+}
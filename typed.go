@@ -0,0 +1,144 @@
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is the error held by a KeyError when the key does not exist.
+var ErrNotFound = errors.New("key not found")
+
+// KeyError is returned by typed getters such as GetInt when a value is
+// missing or cannot be converted. Its message names the setting, so it can be
+// shown to users as is.
+type KeyError struct {
+	Section string
+	Key     string
+	Value   string // raw value, empty if missing
+	Err     error  // ErrNotFound, or the conversion error
+}
+
+func (e *KeyError) Error() string {
+	if e.Err == ErrNotFound {
+		return fmt.Sprintf("%s.%s: %s", e.Section, e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s.%s: invalid value %q: %s", e.Section, e.Key, e.Value, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// EnumError is the error held by a KeyError when a value is not one of the
+// allowed values passed to GetEnum.
+type EnumError struct {
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return "must be one of: " + strings.Join(e.Allowed, ", ")
+}
+
+// getTyped gets a value with get and converts it with parse
+func getTyped[T any](get func(section, key string) (string, bool), section, key string, parse func(string) (T, error)) (T, error) {
+	v, ok := get(section, key)
+	if !ok {
+		var zero T
+		return zero, &KeyError{Section: section, Key: key, Err: ErrNotFound}
+	}
+	res, err := parse(v)
+	if err != nil {
+		return res, &KeyError{Section: section, Key: key, Value: v, Err: numError(err)}
+	}
+	return res, nil
+}
+
+func parseInt(v string) (int, error) {
+	n, err := strconv.ParseInt(v, 0, strconv.IntSize)
+	return int(n), err
+}
+
+func parseInt64(v string) (int64, error) {
+	return strconv.ParseInt(v, 0, 64)
+}
+
+func parseFloat(v string) (float64, error) {
+	return strconv.ParseFloat(v, 64)
+}
+
+// GetInt returns a value as an int. Prefixes such as 0x are accepted.
+func (i Ini) GetInt(section, key string) (int, error) {
+	return getTyped(i.Get, section, key, parseInt)
+}
+
+// GetInt64 returns a value as an int64. Prefixes such as 0x are accepted.
+func (i Ini) GetInt64(section, key string) (int64, error) {
+	return getTyped(i.Get, section, key, parseInt64)
+}
+
+// GetFloat returns a value as a float64.
+func (i Ini) GetFloat(section, key string) (float64, error) {
+	return getTyped(i.Get, section, key, parseFloat)
+}
+
+// GetBool returns a value as a bool, as accepted by strconv.ParseBool.
+func (i Ini) GetBool(section, key string) (bool, error) {
+	return getTyped(i.Get, section, key, strconv.ParseBool)
+}
+
+// GetDuration returns a value as a time.Duration, such as "1m30s".
+func (i Ini) GetDuration(section, key string) (time.Duration, error) {
+	return getTyped(i.Get, section, key, time.ParseDuration)
+}
+
+// parseEnum returns a function converting a value to one of allowed
+func parseEnum(allowed []string) func(string) (string, error) {
+	return func(v string) (string, error) {
+		for _, a := range allowed {
+			if strings.EqualFold(v, a) {
+				return a, nil
+			}
+		}
+		return "", &EnumError{Allowed: allowed}
+	}
+}
+
+// GetEnum returns a value which must be one of allowed, compared without
+// regard to case, and returns it as spelled in allowed. Other values cause
+// a *KeyError holding an *EnumError which lists the allowed values.
+func (i Ini) GetEnum(section, key string, allowed ...string) (string, error) {
+	return getTyped(i.Get, section, key, parseEnum(allowed))
+}
+
+// GetInt returns a value as an int, see Ini.GetInt.
+func (i *IniSafe) GetInt(section, key string) (int, error) {
+	return getTyped(i.Get, section, key, parseInt)
+}
+
+// GetInt64 returns a value as an int64, see Ini.GetInt64.
+func (i *IniSafe) GetInt64(section, key string) (int64, error) {
+	return getTyped(i.Get, section, key, parseInt64)
+}
+
+// GetFloat returns a value as a float64.
+func (i *IniSafe) GetFloat(section, key string) (float64, error) {
+	return getTyped(i.Get, section, key, parseFloat)
+}
+
+// GetBool returns a value as a bool, see Ini.GetBool.
+func (i *IniSafe) GetBool(section, key string) (bool, error) {
+	return getTyped(i.Get, section, key, strconv.ParseBool)
+}
+
+// GetDuration returns a value as a time.Duration.
+func (i *IniSafe) GetDuration(section, key string) (time.Duration, error) {
+	return getTyped(i.Get, section, key, time.ParseDuration)
+}
+
+// GetEnum returns a value which must be one of allowed, see Ini.GetEnum.
+func (i *IniSafe) GetEnum(section, key string, allowed ...string) (string, error) {
+	return getTyped(i.Get, section, key, parseEnum(allowed))
+}
@@ -0,0 +1,64 @@
+package ini_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestTypedGetters(t *testing.T) {
+	i := ini.MustParse("[server]\nport=0x1f90\nratio=0.5\ndebug=true\ntimeout=1m30s\nworkers=many\n")
+
+	if v, err := i.GetInt("server", "port"); err != nil || v != 8080 {
+		t.Errorf("unexpected int %d, %v", v, err)
+	}
+	if v, err := i.GetInt64("server", "port"); err != nil || v != 8080 {
+		t.Errorf("unexpected int64 %d, %v", v, err)
+	}
+	if v, err := i.GetFloat("server", "ratio"); err != nil || v != 0.5 {
+		t.Errorf("unexpected float %g, %v", v, err)
+	}
+	if v, err := i.GetBool("server", "debug"); err != nil || !v {
+		t.Errorf("unexpected bool %t, %v", v, err)
+	}
+	if v, err := i.GetDuration("server", "timeout"); err != nil || v != 90*time.Second {
+		t.Errorf("unexpected duration %s, %v", v, err)
+	}
+
+	_, err := i.GetInt("server", "workers")
+	var kerr *ini.KeyError
+	if !errors.As(err, &kerr) || kerr.Section != "server" || kerr.Key != "workers" || kerr.Value != "many" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("conversion error not wrapped: %v", err)
+	}
+	if s := err.Error(); s != `server.workers: invalid value "many": invalid syntax` {
+		t.Errorf("unexpected message %q", s)
+	}
+
+	_, err = i.GetBool("server", "missing")
+	if !errors.Is(err, ini.ErrNotFound) || !errors.As(err, &kerr) || kerr.Key != "missing" {
+		t.Errorf("unexpected error %v for a missing key", err)
+	}
+}
+
+func TestGetEnum(t *testing.T) {
+	i := ini.MustParse("[log]\nlevel=Debug\nformat=xml\n")
+
+	if v, err := i.GetEnum("log", "level", "debug", "info", "warn"); err != nil || v != "debug" {
+		t.Errorf("unexpected value %q, %v", v, err)
+	}
+
+	_, err := i.GetEnum("log", "format", "text", "json")
+	var eerr *ini.EnumError
+	if !errors.As(err, &eerr) || len(eerr.Allowed) != 2 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if s := err.Error(); s != `log.format: invalid value "xml": must be one of: text, json` {
+		t.Errorf("unexpected message %q", s)
+	}
+}
@@ -0,0 +1,222 @@
+package ini
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Spacing controls the spaces written around the '=' separating keys and
+// values.
+type Spacing int
+
+const (
+	SpacingNone     Spacing = iota // key=value
+	SpacingAround                  // key = value
+	SpacingPreserve                // as found in the source, only supported by Format
+)
+
+// delimiter returns the delimiter written between keys and values
+func (s Spacing) delimiter() string {
+	if s == SpacingAround {
+		return " = "
+	}
+	return "="
+}
+
+// WriteOptions holds settings used when generating ini data. The zero value
+// produces the same output as Write.
+type WriteOptions struct {
+	// Align pads keys with spaces so the '=' signs of a section line up.
+	Align bool
+
+	// Spacing controls spaces around '='. SpacingPreserve cannot be applied
+	// since an Ini does not keep the layout of its source, and behaves like
+	// SpacingNone.
+	Spacing Spacing
+
+	// SectionLess and KeyLess, if set, replace the alphabetical order of
+	// sections and of the keys of a section. The root section is always
+	// written first, as its values could not be read back otherwise.
+	SectionLess func(a, b string) bool
+	KeyLess     func(section, a, b string) bool
+
+	// Quote controls how values are quoted. The default, QuoteRaw, writes
+	// values as is, which is how a plain Load reads them, and fails only on
+	// values containing line breaks. Other styles quote values that could
+	// not be read back otherwise, for output loaded with
+	// LoadOptions.Unquote. With QuoteNever, writing fails if a value cannot
+	// be read back without quotes.
+	Quote QuoteStyle
+
+	// ASCII causes non-ASCII characters to be written as \uXXXX escapes in
+	// double quoted values, for consumers unable to read UTF-8. It requires
+	// a quote style other than QuoteRaw and QuoteNever.
+	ASCII bool
+
+	// BlankLines is the number of blank lines between sections. Zero means
+	// the default of one, use -1 for none.
+	BlankLines int
+
+	// BlankBeforeComment adds a blank line before comments that follow a
+	// value, separating them from the previous value.
+	BlankBeforeComment bool
+
+	// NoTrailingBlankLine omits the blank line written at the end of the
+	// output, after the last value.
+	NoTrailingBlankLine bool
+
+	// Comments, if set, are written along with the values. They are
+	// typically collected with LoadOptions.
+	Comments *Comments
+
+	// RepeatedSections lists sections which can appear multiple times, see
+	// LoadOptions.RepeatedSections. Their instances, stored as "name[0]",
+	// "name[1]" and so on, are all written with a "[name]" header, in
+	// order.
+	RepeatedSections []string
+
+	// Strict causes writing to fail before anything is written if loading
+	// the output would not reproduce the exact same sections, keys and
+	// values, such as for names with uppercase letters or invalid
+	// characters, values with surrounding spaces written with QuoteRaw, or
+	// repeated sections stored outside of numbered instances. Output is
+	// loaded with a plain Load for QuoteRaw, and with LoadOptions.Unquote
+	// for other styles.
+	Strict bool
+}
+
+func (opts *WriteOptions) sectionBlankLines() int {
+	if opts.BlankLines == 0 {
+		return 1
+	}
+	return opts.BlankLines
+}
+
+// WriteWithOptions generates a ini file like Write, using the given options.
+// opts can be nil.
+func (i Ini) WriteWithOptions(d io.Writer, opts *WriteOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	if opts.Strict {
+		if err := i.checkLossless(opts); err != nil {
+			return err
+		}
+	}
+
+	enc := NewEncoderWithOptions(d, opts)
+	c := opts.Comments
+	if c == nil {
+		c = &Comments{}
+	}
+	enc.writeHeader(c.header)
+
+	sections := i.sortedSections()
+	if opts.SectionLess != nil || opts.RepeatedSections != nil {
+		rest := sections
+		if len(rest) > 0 && rest[0] == "root" {
+			rest = rest[1:]
+		}
+		less := opts.SectionLess
+		if less == nil {
+			less = func(a, b string) bool { return a < b }
+		}
+		sort.SliceStable(rest, func(a, b int) bool {
+			na, ia, _ := repeatedBase(rest[a], opts.RepeatedSections)
+			nb, ib, _ := repeatedBase(rest[b], opts.RepeatedSections)
+			if na != nb {
+				return less(na, nb)
+			}
+			return ia < ib
+		})
+	}
+
+	for _, n := range sections {
+		if n != "root" {
+			header, _, _ := repeatedBase(n, opts.RepeatedSections)
+			enc.writeSection(header, c.sections[n])
+		}
+		i.writeSection(enc, n, opts, c)
+	}
+	enc.writeComment(c.footer)
+
+	return enc.Close()
+}
+
+func (i Ini) writeSection(enc *Encoder, section string, opts *WriteOptions, c *Comments) {
+	s := i[section]
+	keys := sortedKeys(s)
+	if opts.KeyLess != nil {
+		sort.SliceStable(keys, func(a, b int) bool { return opts.KeyLess(section, keys[a], keys[b]) })
+	}
+
+	width := 0
+	if opts.Align {
+		for _, k := range keys {
+			width = max(width, len(k))
+		}
+	}
+
+	for _, k := range keys {
+		// errors are returned by Close
+		enc.writeComment(c.keys[section+"\x00"+k])
+		enc.writeKey(k, s[k], width)
+	}
+}
+
+// WriteWithOptions generates a ini file using the given options, see
+// Ini.WriteWithOptions. No lock is held while writing.
+func (i *IniSafe) WriteWithOptions(d io.Writer, opts *WriteOptions) error {
+	return i.snapshot().WriteWithOptions(d, opts)
+}
+
+// readBack returns the value a written value q is loaded as: as is with a
+// plain Load for QuoteRaw, and unquoted with LoadOptions.Unquote otherwise
+func readBack(q string, style QuoteStyle) string {
+	q = strings.TrimSpace(q)
+	if style == QuoteRaw {
+		return q
+	}
+	return unquoteValue(q)
+}
+
+// checkLossless returns an error naming the first section or key that would
+// not be loaded back identically
+func (i Ini) checkLossless(opts *WriteOptions) error {
+	instances := make(map[string]int) // number of instances of repeated sections
+	for _, n := range i.sortedSections() {
+		header, _, ok := repeatedBase(n, opts.RepeatedSections)
+		if err := checkSection(header); err != nil || n != strings.ToLower(n) {
+			return fmt.Errorf("cannot write section %q losslessly: invalid name", n)
+		}
+		if ok {
+			instances[header]++
+		} else if isRepeated(n, opts.RepeatedSections) {
+			// would be loaded back as an instance
+			return fmt.Errorf("cannot write section %q losslessly: repeated sections must be stored as instances", n)
+		}
+		s := i[n]
+		for _, k := range sortedKeys(s) {
+			if err := checkKey(n, k); err != nil || k != strings.ToLower(k) {
+				return fmt.Errorf("cannot write key %q of section %s losslessly: invalid name", k, n)
+			}
+			if q, err := quoteStyle(s[k], opts.Quote, opts.ASCII); err == nil {
+				if v := readBack(q, opts.Quote); v != s[k] {
+					return fmt.Errorf("cannot write %s.%s losslessly: value needs quotes", n, k)
+				}
+			}
+		}
+	}
+	for base, count := range instances {
+		// instances are numbered from 0 when loaded
+		for idx := 0; idx < count; idx++ {
+			if _, ok := i[base+"["+strconv.Itoa(idx)+"]"]; !ok {
+				return fmt.Errorf("cannot write section %q losslessly: instances are not numbered from 0", base)
+			}
+		}
+	}
+	return nil
+}
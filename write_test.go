@@ -0,0 +1,180 @@
+package ini_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestWriteAlign(t *testing.T) {
+	i := ini.MustParse("a=1\n[server]\nhost=localhost\nport=80\nlisten_address=::\n")
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Align: true}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "a=1\n\n[server]\nhost          =localhost\nlisten_address=::\nport          =80\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	if res := ini.MustParse(buf.String()); len(res.Diff(i)) != 0 {
+		t.Errorf("aligned output does not load back to the same values")
+	}
+}
+
+func TestWriteSpacing(t *testing.T) {
+	i := ini.MustParse("a=1\n[s]\nkey=value\nlonger=2\n")
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Spacing: ini.SpacingAround, Align: true}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "a = 1\n\n[s]\nkey    = value\nlonger = 2\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestWriteOrder(t *testing.T) {
+	i := ini.MustParse("z=1\n[b]\nx=1\n[general]\nname=test\nid=1\n[a]\ny=1\n")
+
+	first := func(name string) func(a, b string) bool {
+		return func(a, b string) bool {
+			if a == name || b == name {
+				return a == name && b != name
+			}
+			return a < b
+		}
+	}
+	opts := &ini.WriteOptions{
+		SectionLess: first("general"),
+		KeyLess: func(section, a, b string) bool {
+			return first("name")(a, b)
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, opts); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "z=1\n\n[general]\nname=test\nid=1\n\n[a]\ny=1\n\n[b]\nx=1\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestWriteQuote(t *testing.T) {
+	i := ini.New()
+	i.Set("root", "plain", "value")
+	i.Set("root", "padded", " value ")
+	i.Set("root", "multi", "a\nb")
+
+	tests := []struct {
+		style  ini.QuoteStyle
+		expect string
+	}{
+		{ini.QuoteMinimal, "multi=\"a\\nb\"\npadded=\" value \"\nplain=value\n\n"},
+		{ini.QuoteAlways, "multi=\"a\\nb\"\npadded=\" value \"\nplain=\"value\"\n\n"},
+		{ini.QuoteSingle, "multi=\"a\\nb\"\npadded=' value '\nplain=value\n\n"},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		if err := i.WriteWithOptions(buf, &ini.WriteOptions{Quote: test.style}); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+		if buf.String() != test.expect {
+			t.Errorf("unexpected output for style %d: %q", test.style, buf.String())
+		}
+		if res := mustParseUnquote(buf.String()); len(res.Diff(i)) != 0 {
+			t.Errorf("output for style %d does not load back to the same values", test.style)
+		}
+	}
+
+	if err := i.WriteWithOptions(&bytes.Buffer{}, &ini.WriteOptions{Quote: ini.QuoteNever}); err == nil {
+		t.Errorf("expected an error for values needing quotes")
+	}
+}
+
+func TestWriteASCII(t *testing.T) {
+	i := ini.New()
+	i.Set("root", "name", "café 😀")
+	i.Set("root", "plain", "value")
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Quote: ini.QuoteMinimal, ASCII: true}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	expect := "name=\"caf\\u00e9 \\U0001f600\"\nplain=value\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+	if res := mustParseUnquote(buf.String()); len(res.Diff(i)) != 0 {
+		t.Errorf("output does not load back to the same values")
+	}
+
+	// invalid escape sequences are kept as is
+	if v, _ := mustParseUnquote("a=\"\\ud800 \\xyz\"\n").Get("root", "a"); v != `\ud800 \xyz` {
+		t.Errorf("unexpected value %q", v)
+	}
+	if err := i.WriteWithOptions(&bytes.Buffer{}, &ini.WriteOptions{ASCII: true}); err == nil {
+		t.Errorf("expected an error for ASCII output without quotes")
+	}
+}
+
+func TestWriteRaw(t *testing.T) {
+	i := ini.New()
+	i.Set("root", "path", `C:\temp\"x"`)
+	i.Set("root", "quoted", `"value"`)
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Quote: ini.QuoteRaw}); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	expect := "path=C:\\temp\\\"x\"\nquoted=\"value\"\n\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	i.Set("root", "multi", "a\nb")
+	if err := i.WriteWithOptions(&bytes.Buffer{}, &ini.WriteOptions{Quote: ini.QuoteRaw}); err == nil {
+		t.Errorf("expected an error for a value with a line break")
+	}
+}
+
+func TestWriteStrict(t *testing.T) {
+	i := ini.MustParse("a=1\n[s]\nb=\" padded \"\n")
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteWithOptions(buf, &ini.WriteOptions{Strict: true}); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	padded := ini.Ini{"s": {"b": " padded "}}
+	if err := padded.WriteWithOptions(buf, &ini.WriteOptions{Strict: true, Quote: ini.QuoteMinimal}); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	for _, test := range []struct {
+		data ini.Ini
+		opts ini.WriteOptions
+	}{
+		{ini.Ini{"Upper": {"a": "1"}}, ini.WriteOptions{Strict: true}},
+		{ini.Ini{"s": {"a=b": "1"}}, ini.WriteOptions{Strict: true}},
+		{ini.Ini{"s]": {"a": "1"}}, ini.WriteOptions{Strict: true}},
+		{padded, ini.WriteOptions{Strict: true}},
+	} {
+		buf.Reset()
+		if err := test.data.WriteWithOptions(buf, &test.opts); err == nil {
+			t.Errorf("expected an error writing %v", test.data)
+		} else if buf.Len() != 0 {
+			t.Errorf("data written before failing")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ini
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML returns a yaml document where each section is a mapping of keys to
+// values. Entries of the root section are stored under "root".
+func (i Ini) ToYAML() ([]byte, error) {
+	return yaml.Marshal(map[string]map[string]string(i))
+}
+
+// FromYAML parses a yaml document made of a two-level mapping (section, then
+// key) and returns the matching Ini. Top-level scalars are stored in the root
+// section.
+func FromYAML(data []byte) (Ini, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	res := New()
+	if len(doc.Content) == 0 {
+		// empty document
+		return res, nil
+	}
+
+	top := doc.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return nil, errors.New("failed to parse yaml: document is not a mapping")
+	}
+
+	for n := 0; n+1 < len(top.Content); n += 2 {
+		k, v := top.Content[n], top.Content[n+1]
+
+		switch v.Kind {
+		case yaml.ScalarNode:
+			res.Set("root", k.Value, yamlScalar(v))
+		case yaml.MappingNode:
+			for m := 0; m+1 < len(v.Content); m += 2 {
+				sk, sv := v.Content[m], v.Content[m+1]
+				if sv.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("failed to parse yaml: value of %s.%s is not a scalar", k.Value, sk.Value)
+				}
+				res.Set(k.Value, sk.Value, yamlScalar(sv))
+			}
+		default:
+			return nil, fmt.Errorf("failed to parse yaml: unsupported value for %s", k.Value)
+		}
+	}
+
+	return res, nil
+}
+
+func yamlScalar(n *yaml.Node) string {
+	if n.ShortTag() == "!!null" {
+		return ""
+	}
+	return n.Value
+}
@@ -0,0 +1,39 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/ini"
+)
+
+func TestYAML(t *testing.T) {
+	src := ini.New()
+	src.Set("root", "var1", "value1")
+	src.Set("section", "var2", "0x10")
+
+	buf, err := src.ToYAML()
+	if err != nil {
+		t.Fatalf("failed to export yaml: %s", err)
+	}
+
+	res, err := ini.FromYAML(buf)
+	if err != nil {
+		t.Fatalf("failed to parse yaml: %s", err)
+	}
+
+	if v, ok := res.Get("section", "var2"); !ok || v != "0x10" {
+		t.Errorf("failed to get value section/var2, read %#v %#v", v, ok)
+	}
+
+	res, err = ini.FromYAML([]byte("var1: value1\nsection:\n  Var2: true\n"))
+	if err != nil {
+		t.Fatalf("failed to parse yaml: %s", err)
+	}
+
+	if v, ok := res.Get("root", "var1"); !ok || v != "value1" {
+		t.Errorf("failed to get value root/var1, read %#v %#v", v, ok)
+	}
+	if v, ok := res.Get("section", "var2"); !ok || v != "true" {
+		t.Errorf("failed to get value section/var2, read %#v %#v", v, ok)
+	}
+}